@@ -0,0 +1,182 @@
+package bookmarks
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/ismaelpadilla/gotorrent/xdg"
+)
+
+// Bookmark is a torrent saved by the user to come back to later.
+type Bookmark struct {
+	// Key is the torrent's interfaces.Torrent.Key, a torrent has a single
+	// bookmark
+	Key        string    `json:"key"`
+	InfoHash   string    `json:"info_hash"`
+	Title      string    `json:"title"`
+	MagnetLink string    `json:"magnet_link"`
+	Provider   string    `json:"provider"`
+	ID         string    `json:"id"`
+	Size       int       `json:"size"`
+	Note       string    `json:"note,omitempty"`
+	Added      time.Time `json:"added"`
+}
+
+// Store keeps bookmarks in a JSON file.
+type Store struct {
+	path string
+}
+
+// DefaultPath returns the location of the bookmarks file.
+func DefaultPath() string {
+	return filepath.Join(xdg.DataDir(), "bookmarks.json")
+}
+
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// FromTorrent creates a bookmark for a torrent.
+func FromTorrent(t interfaces.Torrent, note string) Bookmark {
+	provider := ""
+	if t.Client != nil {
+		provider = t.Client.Name()
+	}
+	return Bookmark{
+		Key:        t.Key(),
+		InfoHash:   strings.ToLower(t.InfoHash),
+		Title:      t.Title,
+		MagnetLink: t.MagnetLink,
+		Provider:   provider,
+		ID:         t.ID,
+		Size:       t.Size,
+		Note:       note,
+		Added:      time.Now(),
+	}
+}
+
+// Torrent converts the bookmark back to a torrent, using client to fetch its
+// details. client may be nil if the provider is no longer available.
+func (b Bookmark) Torrent(client interfaces.Client) interfaces.Torrent {
+	return interfaces.Torrent{
+		Client:     client,
+		ID:         b.ID,
+		Title:      b.Title,
+		InfoHash:   b.InfoHash,
+		MagnetLink: b.MagnetLink,
+		Size:       b.Size,
+		Release:    release.Parse(b.Title),
+	}
+}
+
+// List returns every bookmark, oldest first.
+// A missing bookmarks file is not an error.
+func (s *Store) List() ([]Bookmark, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return decode(file)
+}
+
+// Add saves a bookmark. If a bookmark for the same torrent already exists it
+// is replaced.
+func (s *Store) Add(b Bookmark) error {
+	bookmarks, err := s.List()
+	if err != nil {
+		return err
+	}
+	return s.save(merge(bookmarks, []Bookmark{b}))
+}
+
+// Remove deletes the bookmark of the torrent with the given key, see
+// interfaces.Torrent.Key.
+func (s *Store) Remove(key string) error {
+	bookmarks, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	kept := bookmarks[:0]
+	for _, b := range bookmarks {
+		if b.Key != key {
+			kept = append(kept, b)
+		}
+	}
+	return s.save(kept)
+}
+
+// Export writes every bookmark to w as JSON.
+func (s *Store) Export(w io.Writer) error {
+	bookmarks, err := s.List()
+	if err != nil {
+		return err
+	}
+	if bookmarks == nil {
+		bookmarks = []Bookmark{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bookmarks)
+}
+
+// Import reads bookmarks exported with Export and adds them to the store.
+// It returns the number of imported bookmarks.
+func (s *Store) Import(r io.Reader) (int, error) {
+	imported, err := decode(r)
+	if err != nil {
+		return 0, err
+	}
+	bookmarks, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	return len(imported), s.save(merge(bookmarks, imported))
+}
+
+func (s *Store) save(bookmarks []Bookmark) error {
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(s.path, data)
+}
+
+func decode(r io.Reader) ([]Bookmark, error) {
+	var bookmarks []Bookmark
+	if err := json.NewDecoder(r).Decode(&bookmarks); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+// merge adds bookmarks to existing ones, replacing those for the same
+// torrent
+func merge(existing []Bookmark, added []Bookmark) []Bookmark {
+	for _, a := range added {
+		replaced := false
+		for i, e := range existing {
+			if e.Key == a.Key {
+				existing[i] = a
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, a)
+		}
+	}
+	return existing
+}
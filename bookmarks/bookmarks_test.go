@@ -0,0 +1,83 @@
+package bookmarks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// fakeClient is a provider that can't do anything but tell its name
+type fakeClient struct{ interfaces.Client }
+
+func (fakeClient) Name() string { return "nyaa" }
+
+func TestFromTorrent(t *testing.T) {
+	tests := []struct {
+		torrent interfaces.Torrent
+		want    string
+	}{
+		{interfaces.Torrent{InfoHash: "ABC", Client: fakeClient{}, ID: "1"}, "abc"},
+		{interfaces.Torrent{Client: fakeClient{}, ID: "1", MagnetLink: "magnet:?dn=a"}, "id:nyaa:1"},
+		{interfaces.Torrent{MagnetLink: "magnet:?dn=a", Title: "a"}, "link:magnet:?dn=a"},
+		{interfaces.Torrent{Title: "a"}, "title:a"},
+	}
+	for _, tt := range tests {
+		b := FromTorrent(tt.torrent, "note")
+		if b.Key != tt.want || b.Key != tt.torrent.Key() {
+			t.Errorf("FromTorrent(%+v).Key = %q, want %q", tt.torrent, b.Key, tt.want)
+		}
+		// the torrent is the same once the bookmark is converted back
+		if got := b.Torrent(tt.torrent.Client).Key(); got != b.Key {
+			t.Errorf("%+v.Torrent().Key() = %q, want %q", b, got, b.Key)
+		}
+	}
+}
+
+func TestAddAndRemove(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "bookmarks.json"))
+	for _, b := range []Bookmark{
+		{Key: "abc", InfoHash: "abc", Title: "hash"},
+		{Key: "abc", InfoHash: "abc", Title: "same hash"},
+		{Key: "id:rss:1", Provider: "rss", ID: "1", Title: "first"},
+		{Key: "id:rss:2", Provider: "rss", ID: "2", Title: "second"},
+		{Key: "id:local:1", Provider: "local", ID: "1", Title: "other provider"},
+		{Key: "id:rss:1", Provider: "rss", ID: "1", Title: "first again"},
+	} {
+		if err := s.Add(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, b := range list {
+		titles = append(titles, b.Title)
+	}
+	want := []string{"same hash", "first again", "second", "other provider"}
+	if len(titles) != len(want) {
+		t.Fatalf("bookmarks = %q, want %q", titles, want)
+	}
+	for i := range want {
+		if titles[i] != want[i] {
+			t.Fatalf("bookmarks = %q, want %q", titles, want)
+		}
+	}
+
+	// removing a torrent without info-hash keeps the others
+	if err := s.Remove("id:rss:1"); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := s.List(); len(list) != 3 {
+		t.Errorf("%d bookmarks left, want 3", len(list))
+	}
+	if err := s.Remove("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := s.List(); len(list) != 2 {
+		t.Errorf("%d bookmarks left, want 2", len(list))
+	}
+}
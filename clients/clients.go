@@ -0,0 +1,117 @@
+package clients
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/clients/eztv"
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/clients/local"
+	"github.com/ismaelpadilla/gotorrent/clients/nyaa"
+	"github.com/ismaelpadilla/gotorrent/clients/rss"
+	"github.com/ismaelpadilla/gotorrent/clients/thepiratebay"
+	"github.com/ismaelpadilla/gotorrent/clients/x1337"
+	"github.com/ismaelpadilla/gotorrent/clients/yts"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// Config holds the options of the providers that have any.
+type Config struct {
+	X1337 x1337.Config
+	RSS   []rss.Feed
+	Local local.Config
+	// HTTP configures the requests of every provider
+	HTTP httpclient.Config
+	// ProviderHTTP replaces options of HTTP for some providers, by name
+	ProviderHTTP map[string]httpclient.Config
+}
+
+var config Config
+
+// the HTTP clients of the providers, by name
+var httpClients = map[string]*httpclient.Client{}
+
+// sharedHTTPClient makes the requests that aren't a provider's
+var sharedHTTPClient = httpclient.Default
+
+// every available provider, by name
+var providers = map[string]func() interfaces.Client{
+	"tpb":   func() interfaces.Client { return thepiratebay.New(httpClient("tpb")) },
+	"nyaa":  func() interfaces.Client { return nyaa.New(httpClient("nyaa")) },
+	"1337x": func() interfaces.Client { return x1337.New(config.X1337, httpClient("1337x")) },
+	"yts":   func() interfaces.Client { return yts.New(httpClient("yts")) },
+	"eztv":  func() interfaces.Client { return eztv.New(httpClient("eztv")) },
+	"rss":   func() interfaces.Client { return rss.New(config.RSS, httpClient("rss")) },
+	"local": func() interfaces.Client { return local.New(config.Local) },
+}
+
+// Configure sets the options used by the clients returned by Get. It returns
+// an error if the HTTP options of a provider aren't valid.
+func Configure(c Config) error {
+	for name := range c.ProviderHTTP {
+		if _, ok := providers[name]; !ok {
+			return fmt.Errorf("unknown provider %q in HTTP options", name)
+		}
+	}
+	shared, err := httpclient.New(c.HTTP)
+	if err != nil {
+		return err
+	}
+	clients := make(map[string]*httpclient.Client, len(providers))
+	for name := range providers {
+		client, err := httpclient.New(c.HTTP.Merge(c.ProviderHTTP[name]))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		clients[name] = client
+	}
+	config = c
+	httpClients = clients
+	sharedHTTPClient = shared
+	return nil
+}
+
+// HTTPClient returns the HTTP client used for requests that aren't made by a
+// provider, such as downloading .torrent files.
+func HTTPClient() *httpclient.Client {
+	return sharedHTTPClient
+}
+
+// httpClient returns the HTTP client of a provider
+func httpClient(name string) *httpclient.Client {
+	if client, ok := httpClients[name]; ok {
+		return client
+	}
+	return httpclient.Default
+}
+
+// Get returns the client for the provider with the given name. A single RSS
+// feed is a provider named rss:<feed name>.
+func Get(name string) (interfaces.Client, error) {
+	if strings.HasPrefix(name, "rss:") {
+		feedName := strings.TrimPrefix(name, "rss:")
+		for _, feed := range config.RSS {
+			if feed.Name == feedName {
+				return rss.New([]rss.Feed{feed}, httpClient("rss")), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown RSS feed %q", feedName)
+	}
+
+	newClient, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+	return newClient(), nil
+}
+
+// Names returns the names of all available providers.
+func Names() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,195 @@
+package eztv
+
+import (
+	"encoding/json"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const (
+	baseURL = "https://eztv.re"
+	// maximum number of torrents per page allowed by the API
+	pageLimit = 100
+	// maximum number of pages fetched at once
+	maxPages = 5
+)
+
+var imdbRegexp = regexp.MustCompile(`^(?i)tt(\d+)$`)
+
+func New(web *httpclient.Client) interfaces.Client {
+	return eztv{web: web, base: baseURL}
+}
+
+func (e eztv) Name() string {
+	return "eztv"
+}
+
+func (e eztv) Search(query string) []interfaces.Torrent {
+	torrents, _ := e.SearchPage(query, "")
+	return torrents
+}
+
+// Latest returns the first page of the latest torrents
+func (e eztv) Latest() []interfaces.Torrent {
+	return e.Search("")
+}
+
+// SearchPage looks for a show's torrents when the query has its IMDb id, such
+// as "tt0903747 S02E05". Other words in the query, like the season and
+// episode, narrow the results down. EZTV can't search by keywords, so
+// queries without an IMDb id are matched against the latest torrents. Pages
+// are fetched until one has matches, up to maxPages at once, so a page of
+// results can be empty while there are more pages to look at. page is the
+// number of the first page to fetch.
+func (e eztv) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	imdbID := ""
+	var words []string
+	for _, word := range strings.Fields(query) {
+		if m := imdbRegexp.FindStringSubmatch(word); m != nil && imdbID == "" {
+			imdbID = m[1]
+			continue
+		}
+		words = append(words, word)
+	}
+	wanted := release.Parse(strings.Join(words, " "))
+
+	first, err := strconv.Atoi(page)
+	if err != nil || first < 1 {
+		first = 1
+	}
+
+	var torrents []interfaces.Torrent
+	next := ""
+	for number := first; number < first+maxPages; number++ {
+		url := e.base + "/api/get-torrents?limit=" + strconv.Itoa(pageLimit) + "&page=" + strconv.Itoa(number)
+		if imdbID != "" {
+			url += "&imdb_id=" + imdbID
+		}
+		response := e.get(url)
+		for _, et := range response.Torrents {
+			t := et.convert()
+			if !matches(t, words, wanted) {
+				continue
+			}
+			t.Client = e
+			torrents = append(torrents, t)
+		}
+
+		if number*pageLimit >= response.TorrentsCount || len(response.Torrents) == 0 {
+			next = ""
+			break
+		}
+		next = strconv.Itoa(number + 1)
+		// a show has few pages, they're all fetched at once, but the latest
+		// torrents go on and on
+		if imdbID == "" && len(torrents) > 0 {
+			break
+		}
+	}
+	return torrents, next
+}
+
+// matches returns true if the torrent has the season and episode in wanted,
+// and its title has the rest of the words
+func matches(t interfaces.Torrent, words []string, wanted release.Info) bool {
+	if wanted.Season > 0 && t.Release.Season != wanted.Season {
+		return false
+	}
+	if wanted.Episode > 0 && t.Release.Episode != wanted.Episode {
+		return false
+	}
+	title := strings.ToLower(t.Title)
+	for _, word := range words {
+		if release.Parse(word).SeasonEpisode() != "" {
+			continue
+		}
+		if !strings.Contains(title, strings.ToLower(word)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (et eztvTorrent) convert() interfaces.Torrent {
+	size, _ := strconv.Atoi(et.SizeBytes)
+	info := release.Parse(et.Title)
+	// the API's season and episode are more reliable than the title's
+	if season, err := strconv.Atoi(et.Season); err == nil && season > 0 {
+		info.Season = season
+	}
+	if episode, err := strconv.Atoi(et.Episode); err == nil && episode > 0 {
+		info.Episode = episode
+	}
+
+	t := interfaces.Torrent{
+		// the ID is the path of the episode's page without the /ep/ prefix,
+		// such as 1789123/the-show-s02e05-1080p/
+		ID:         strings.TrimPrefix(strings.TrimPrefix(et.EpisodeURL, baseURL), "/ep/"),
+		Title:      et.Title,
+		InfoHash:   strings.ToUpper(et.Hash),
+		MagnetLink: et.MagnetURL,
+		Size:       size,
+		Seeders:    et.Seeds,
+		Leechers:   et.Peers,
+		Category:   category(info),
+		Release:    info,
+	}
+	if et.IMDbID != "" && et.IMDbID != "0" {
+		t.IMDbID = "tt" + et.IMDbID
+	}
+	if et.DateReleasedUnix > 0 {
+		t.Uploaded = strconv.FormatInt(et.DateReleasedUnix, 10)
+	}
+	return t
+}
+
+func category(info release.Info) interfaces.Category {
+	switch height := info.ResolutionHeight(); {
+	case height >= 2160:
+		return interfaces.CategoryTV4K
+	case height >= 720:
+		return interfaces.CategoryTVHD
+	case height > 0:
+		return interfaces.CategoryTVSD
+	default:
+		return interfaces.CategoryTV
+	}
+}
+
+func (e eztv) NavigateTo(torrent interfaces.Torrent) {
+	err := open.Run(e.base + "/ep/" + torrent.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
+// FetchTorrentDescription returns an empty description, EZTV torrents don't
+// have one
+func (e eztv) FetchTorrentDescription(torrent interfaces.Torrent) string {
+	return ""
+}
+
+// FetchTorrentFiles returns no files, the API doesn't list them
+func (e eztv) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
+	return []interfaces.TorrentFile{}
+}
+
+func (e eztv) get(url string) eztvResponse {
+	body, err := e.web.Get(url)
+	if err != nil {
+		log.Panic(err)
+	}
+	var response eztvResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		log.Panic(err)
+	}
+	return response
+}
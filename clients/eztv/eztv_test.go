@@ -0,0 +1,237 @@
+package eztv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
+
+// testAPI serves the pages in testdata: show-<page>.json for the show with
+// IMDb id 0903747, and latest-<page>.json, or latest-other.json if there's no
+// such page, for the latest torrents. It records the pages requested.
+type testAPI struct {
+	mu    sync.Mutex
+	pages []string
+}
+
+func (a *testAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.URL.Path != "/api/get-torrents" || q.Get("limit") != "100" {
+		http.NotFound(w, r)
+		return
+	}
+	name := fmt.Sprintf("latest-%s.json", q.Get("page"))
+	if _, err := os.Stat(filepath.Join("testdata", name)); err != nil {
+		name = "latest-other.json"
+	}
+	if q.Get("imdb_id") == "0903747" {
+		name = fmt.Sprintf("show-%s.json", q.Get("page"))
+	}
+
+	a.mu.Lock()
+	a.pages = append(a.pages, name)
+	a.mu.Unlock()
+	http.ServeFile(w, r, filepath.Join("testdata", name))
+}
+
+func newTestClient(t *testing.T) (eztv, *testAPI) {
+	t.Helper()
+	api := &testAPI{}
+	s := httptest.NewServer(api)
+	t.Cleanup(s.Close)
+
+	web, err := httpclient.New(httpclient.Config{Retries: -1, RateLimit: -1, BreakerFailures: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return eztv{web: web, base: s.URL}, api
+}
+
+func titles(torrents []interfaces.Torrent) []string {
+	var t []string
+	for _, torrent := range torrents {
+		t = append(t, torrent.Title)
+	}
+	return t
+}
+
+func TestConvert(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "show-1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response eztvResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+
+	got := response.Torrents[0].convert()
+	want := interfaces.Torrent{
+		ID:         "1700001/breaking-bad-s02e05-720p-hdtv-x264-group/",
+		Title:      "Breaking Bad S02E05 720p HDTV x264-GROUP EZTV",
+		InfoHash:   "EEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEE",
+		MagnetLink: "magnet:?xt=urn:btih:EEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEEE&dn=Breaking+Bad+S02E05+720p+HDTV+x264-GROUP",
+		Size:       1073741824,
+		Seeders:    1,
+		Leechers:   2,
+		Uploaded:   "1661700001",
+		Category:   interfaces.CategoryTVHD,
+		IMDbID:     "tt0903747",
+		Release:    release.Parse("Breaking Bad S02E05 720p HDTV x264-GROUP EZTV"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convert() = %+v, want %+v", got, want)
+	}
+
+	// the size, the IMDb id and the date are optional
+	var empty eztvTorrent
+	if got := empty.convert(); got.Size != 0 || got.IMDbID != "" || got.Uploaded != "" {
+		t.Errorf("convert() of an empty torrent = %+v", got)
+	}
+}
+
+func TestConvertSeasonAndEpisode(t *testing.T) {
+	// the API's season and episode win over the title's
+	got := eztvTorrent{Title: "Show 2x05 720p", Season: "3", Episode: "7"}.convert()
+	if got.Release.Season != 3 || got.Release.Episode != 7 {
+		t.Errorf("season and episode = %d, %d, want 3, 7", got.Release.Season, got.Release.Episode)
+	}
+	got = eztvTorrent{Title: "Show S02E05 720p", Season: "0", Episode: ""}.convert()
+	if got.Release.Season != 2 || got.Release.Episode != 5 {
+		t.Errorf("season and episode = %d, %d, want the title's", got.Release.Season, got.Release.Episode)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := map[string]interfaces.Category{
+		"Show S01E01 2160p WEB":  interfaces.CategoryTV4K,
+		"Show S01E01 1080p WEB":  interfaces.CategoryTVHD,
+		"Show S01E01 720p HDTV":  interfaces.CategoryTVHD,
+		"Show S01E01 480p x264":  interfaces.CategoryTVSD,
+		"Show S01E01 HDTV x264":  interfaces.CategoryTV,
+		"Show S01E01 XviD-GROUP": interfaces.CategoryTV,
+	}
+	for title, want := range tests {
+		if got := category(release.Parse(title)); got != want {
+			t.Errorf("category(%q) = %s, want %s", title, got, want)
+		}
+	}
+}
+
+func TestSearchByIMDb(t *testing.T) {
+	e, api := newTestClient(t)
+
+	torrents, next := e.SearchPage("tt0903747 S02E05", "")
+	want := []string{"Breaking Bad S02E05 720p HDTV x264-GROUP EZTV", "Breaking Bad S02E05 1080p BluRay x264-GROUP EZTV"}
+	if got := titles(torrents); !reflect.DeepEqual(got, want) {
+		t.Errorf("torrents = %q, want %q", got, want)
+	}
+	// the show's 150 torrents are in two pages, both fetched at once
+	if next != "" || !reflect.DeepEqual(api.pages, []string{"show-1.json", "show-2.json"}) {
+		t.Errorf("fetched %q, next page %q, want both pages and no more", api.pages, next)
+	}
+	for _, torrent := range torrents {
+		if torrent.Client == nil || torrent.Client.Name() != "eztv" {
+			t.Errorf("torrent %q has no client", torrent.Title)
+		}
+	}
+
+	torrents, _ = e.SearchPage("tt0903747 1080p", "")
+	if got := titles(torrents); !reflect.DeepEqual(got, []string{"Breaking Bad S02E05 1080p BluRay x264-GROUP EZTV"}) {
+		t.Errorf("torrents = %q, want the 1080p one", got)
+	}
+}
+
+func TestSearchKeywords(t *testing.T) {
+	tests := []struct {
+		query     string
+		page      string
+		want      []string
+		wantPages []string
+		wantNext  string
+	}{
+		{
+			query:     "",
+			want:      []string{"Some Show S03E04 1080p WEB H264-GROUP EZTV", "Other Show S01E10 720p HDTV x264-GROUP EZTV"},
+			wantPages: []string{"latest-1.json"},
+			wantNext:  "2",
+		},
+		{
+			query:     "other show",
+			want:      []string{"Other Show S01E10 720p HDTV x264-GROUP EZTV"},
+			wantPages: []string{"latest-1.json"},
+			wantNext:  "2",
+		},
+		{
+			// pages without matches are skipped
+			query:     "breaking bad s02e05",
+			want:      []string{"Breaking Bad S02E05 1080p BluRay x264-GROUP EZTV"},
+			wantPages: []string{"latest-1.json", "latest-2.json", "latest-3.json"},
+			wantNext:  "4",
+		},
+		{
+			query:     "other show",
+			page:      "2",
+			want:      []string{"Other Show S01E09 480p x264-mSD EZTV"},
+			wantPages: []string{"latest-2.json"},
+			wantNext:  "3",
+		},
+		{
+			// up to maxPages are fetched, the rest are left for later
+			query:     "no such show",
+			page:      "2",
+			wantPages: []string{"latest-2.json", "latest-3.json", "latest-other.json", "latest-other.json", "latest-other.json"},
+			wantNext:  "7",
+		},
+		{
+			// 700 torrents fit in 7 pages
+			query:     "no such show",
+			page:      "6",
+			wantPages: []string{"latest-other.json", "latest-other.json"},
+			wantNext:  "",
+		},
+	}
+	for _, tt := range tests {
+		e, api := newTestClient(t)
+		torrents, next := e.SearchPage(tt.query, tt.page)
+		if got := titles(torrents); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SearchPage(%q, %q) = %q, want %q", tt.query, tt.page, got, tt.want)
+		}
+		if !reflect.DeepEqual(api.pages, tt.wantPages) || next != tt.wantNext {
+			t.Errorf("SearchPage(%q, %q) fetched %q, next page %q, want %q, %q", tt.query, tt.page, api.pages, next, tt.wantPages, tt.wantNext)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	torrent := eztvTorrent{Title: "Breaking Bad S02E05 1080p BluRay x264-GROUP EZTV", Season: "2", Episode: "5"}.convert()
+	tests := map[string]bool{
+		"":                     true,
+		"S02E05":               true,
+		"s02e05 1080p":         true,
+		"breaking bad S02":     true,
+		"S02E06":               false,
+		"S03":                  false,
+		"breaking bad 720p":    false,
+		"BREAKING BAD s02e05":  true,
+		"breaking bad 2x05":    true,
+		"better call saul s02": false,
+	}
+	for query, want := range tests {
+		words := strings.Fields(query)
+		if got := matches(torrent, words, release.Parse(query)); got != want {
+			t.Errorf("matches(%q) = %t, want %t", query, got, want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package eztv
+
+import "github.com/ismaelpadilla/gotorrent/clients/httpclient"
+
+type eztv struct {
+	web *httpclient.Client
+	// base is the address of the site, baseURL outside of tests
+	base string
+}
+
+type eztvResponse struct {
+	TorrentsCount int           `json:"torrents_count"`
+	Limit         int           `json:"limit"`
+	Page          int           `json:"page"`
+	Torrents      []eztvTorrent `json:"torrents"`
+}
+
+type eztvTorrent struct {
+	ID               int    `json:"id"`
+	Hash             string `json:"hash"`
+	Filename         string `json:"filename"`
+	EpisodeURL       string `json:"episode_url"`
+	TorrentURL       string `json:"torrent_url"`
+	MagnetURL        string `json:"magnet_url"`
+	Title            string `json:"title"`
+	IMDbID           string `json:"imdb_id"`
+	Season           string `json:"season"`
+	Episode          string `json:"episode"`
+	Seeds            int    `json:"seeds"`
+	Peers            int    `json:"peers"`
+	DateReleasedUnix int64  `json:"date_released_unix"`
+	SizeBytes        string `json:"size_bytes"`
+}
@@ -0,0 +1,316 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the longest a request can take, including reading
+	// the response, if no timeout is configured
+	DefaultTimeout = 30 * time.Second
+	// DefaultUserAgent is sent if no User-Agent is configured
+	DefaultUserAgent = "gotorrent"
+	// DefaultMaxResponseSize is the largest response read if no limit is
+	// configured, 32MB
+	DefaultMaxResponseSize = 32 << 20
+)
+
+// ErrTooLarge is returned when a response is larger than the configured
+// limit.
+var ErrTooLarge = errors.New("response too large")
+
+// Config holds the options of the HTTP client of a provider. Zero values
+// use the defaults.
+type Config struct {
+	Timeout   time.Duration
+	UserAgent string
+	// Proxy is the URL of an HTTP, HTTPS or SOCKS5 proxy, such as
+	// socks5://localhost:9050. The HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables are used if it's empty.
+	Proxy string
+	// CABundle is a file with PEM encoded certificates trusted besides the
+	// system's, such as a corporate proxy's
+	CABundle string
+	// MaxResponseSize is the largest response read, in bytes
+	MaxResponseSize int64
+
+	// Retries is how many times requests that fail with a 5xx or 429 status
+	// or a timeout are retried, -1 for none
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// of the next ones. Delays are randomized by up to half, and the
+	// Retry-After header is honoured.
+	RetryBackoff time.Duration
+	// MaxRetryDelay is the longest delay before a retry
+	MaxRetryDelay time.Duration
+	// RateLimit is how many requests per second are sent to each host, -1
+	// for no limit
+	RateLimit float64
+	// RateBurst is how many requests can be sent to a host at once
+	RateBurst int
+	// BreakerFailures is how many requests to a host have to fail in a
+	// row for the next ones to fail without being sent, -1 to always send
+	// them
+	BreakerFailures int
+	// BreakerCooldown is how long requests to a host fail after it failed
+	// BreakerFailures times
+	BreakerCooldown time.Duration
+
+	// Clock is the time used to wait, the system's if nil
+	Clock Clock
+}
+
+// Merge returns the config with the options set in other replacing its own.
+func (c Config) Merge(other Config) Config {
+	if other.Timeout != 0 {
+		c.Timeout = other.Timeout
+	}
+	if other.UserAgent != "" {
+		c.UserAgent = other.UserAgent
+	}
+	if other.Proxy != "" {
+		c.Proxy = other.Proxy
+	}
+	if other.CABundle != "" {
+		c.CABundle = other.CABundle
+	}
+	if other.MaxResponseSize != 0 {
+		c.MaxResponseSize = other.MaxResponseSize
+	}
+	if other.Retries != 0 {
+		c.Retries = other.Retries
+	}
+	if other.RetryBackoff != 0 {
+		c.RetryBackoff = other.RetryBackoff
+	}
+	if other.MaxRetryDelay != 0 {
+		c.MaxRetryDelay = other.MaxRetryDelay
+	}
+	if other.RateLimit != 0 {
+		c.RateLimit = other.RateLimit
+	}
+	if other.RateBurst != 0 {
+		c.RateBurst = other.RateBurst
+	}
+	if other.BreakerFailures != 0 {
+		c.BreakerFailures = other.BreakerFailures
+	}
+	if other.BreakerCooldown != 0 {
+		c.BreakerCooldown = other.BreakerCooldown
+	}
+	if other.Clock != nil {
+		c.Clock = other.Clock
+	}
+	return c
+}
+
+// Client makes the requests of a provider. Failed requests are retried,
+// requests to each host are rate limited, and hosts that keep failing are
+// left alone for a while. Providers share the Default client unless they're
+// configured otherwise.
+type Client struct {
+	client          *http.Client
+	userAgent       string
+	maxResponseSize int64
+
+	retries         int
+	retryBackoff    time.Duration
+	maxRetryDelay   time.Duration
+	rateLimit       float64
+	rateBurst       int
+	breakerFailures int
+	breakerCooldown time.Duration
+	clock           Clock
+	// random returns a random number in [0, n), used for the backoff's
+	// jitter
+	random func(n int64) int64
+	hosts  hosts
+}
+
+// Default is the client used when none is configured.
+var Default = mustNew(Config{})
+
+func mustNew(config Config) *Client {
+	c, err := New(config)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// New returns a client with the given options. It returns an error if the
+// proxy URL isn't valid or the CA bundle can't be read.
+func New(config Config) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", config.Proxy, err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy %q: use an http://, https:// or socks5:// URL", config.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if config.CABundle != "" {
+		pem, err := os.ReadFile(config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", config.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	c := &Client{
+		client:          &http.Client{Transport: transport, Timeout: config.Timeout},
+		userAgent:       config.UserAgent,
+		maxResponseSize: config.MaxResponseSize,
+		retries:         config.Retries,
+		retryBackoff:    config.RetryBackoff,
+		maxRetryDelay:   config.MaxRetryDelay,
+		rateLimit:       config.RateLimit,
+		rateBurst:       config.RateBurst,
+		breakerFailures: config.BreakerFailures,
+		breakerCooldown: config.BreakerCooldown,
+		clock:           config.Clock,
+		random:          defaultRandom,
+	}
+	if c.client.Timeout == 0 {
+		c.client.Timeout = DefaultTimeout
+	}
+	if c.userAgent == "" {
+		c.userAgent = DefaultUserAgent
+	}
+	if c.maxResponseSize == 0 {
+		c.maxResponseSize = DefaultMaxResponseSize
+	}
+	if c.retries == 0 {
+		c.retries = DefaultRetries
+	}
+	if c.retryBackoff == 0 {
+		c.retryBackoff = DefaultRetryBackoff
+	}
+	if c.maxRetryDelay == 0 {
+		c.maxRetryDelay = DefaultMaxRetryDelay
+	}
+	if c.rateLimit == 0 {
+		c.rateLimit = DefaultRateLimit
+	}
+	if c.rateBurst <= 0 {
+		c.rateBurst = DefaultRateBurst
+	}
+	if c.breakerFailures == 0 {
+		c.breakerFailures = DefaultBreakerFailures
+	}
+	if c.breakerCooldown == 0 {
+		c.breakerCooldown = DefaultBreakerCooldown
+	}
+	if c.clock == nil {
+		c.clock = realClock{}
+	}
+	return c, nil
+}
+
+// Get fetches a URL and returns the response's body. Responses other than
+// 200 OK are returned as errors.
+func (c *Client) Get(url string) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(request)
+}
+
+// Do sends a request and returns the response's body, like Get. The
+// User-Agent is set unless the request has one. Requests are sent again if
+// they fail with a 5xx or 429 status or a timeout.
+func (c *Client) Do(request *http.Request) ([]byte, error) {
+	if request.Header.Get("User-Agent") == "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+	host := request.URL.Host
+	if err := c.checkHealth(host); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		c.waitTurn(host)
+		body, err := c.send(request)
+		c.recordResult(host, err)
+		if err == nil || !retryable(err) || attempt >= c.retries {
+			return body, err
+		}
+
+		delay := c.backoff(attempt)
+		var status statusError
+		if errors.As(err, &status) && status.retryAfter > delay {
+			if status.retryAfter > c.maxRetryDelay {
+				return nil, fmt.Errorf("%w, retry after %s", err, status.retryAfter)
+			}
+			delay = status.retryAfter
+		}
+		c.clock.Sleep(delay)
+		// the host may have become unhealthy with this failure
+		if err := c.checkHealth(host); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends a request once
+func (c *Client) send(request *http.Request) ([]byte, error) {
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, statusError{
+			url:        request.URL.Redacted(),
+			status:     response.Status,
+			code:       response.StatusCode,
+			retryAfter: parseRetryAfter(response.Header.Get("Retry-After"), c.clock.Now()),
+		}
+	}
+
+	// one more byte is read to know if the limit was exceeded
+	body, err := io.ReadAll(io.LimitReader(response.Body, c.maxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxResponseSize {
+		return nil, fmt.Errorf("%s: %w, the limit is %d bytes", request.URL.Redacted(), ErrTooLarge, c.maxResponseSize)
+	}
+	return body, nil
+}
+
+// WebLink returns true if link is an http or https URL. Links given by
+// providers are checked with it before they're opened.
+func WebLink(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return (scheme == "http" || scheme == "https") && u.Host != ""
+}
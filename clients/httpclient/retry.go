@@ -0,0 +1,214 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRetries is how many times a failed request is retried
+	DefaultRetries = 2
+	// DefaultRetryBackoff is the delay before the first retry, doubled for
+	// each of the next ones
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// DefaultMaxRetryDelay is the longest delay before a retry. Requests
+	// aren't retried if the server asks to wait longer.
+	DefaultMaxRetryDelay = 10 * time.Second
+	// DefaultRateLimit is how many requests per second are sent to a host
+	DefaultRateLimit = 4
+	// DefaultRateBurst is how many requests can be sent to a host at once,
+	// before the rate limit applies
+	DefaultRateBurst = 10
+	// DefaultBreakerFailures is how many requests in a row have to fail for
+	// a host to be considered unhealthy
+	DefaultBreakerFailures = 5
+	// DefaultBreakerCooldown is how long requests to an unhealthy host fail
+	// without being sent
+	DefaultBreakerCooldown = time.Minute
+)
+
+// ErrUnhealthy is returned without sending the request when too many
+// requests to a host have failed recently.
+var ErrUnhealthy = errors.New("host is unhealthy")
+
+// Clock is the time used to wait between requests, replaced by a fake one
+// in tests.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// statusError is returned for responses other than 200 OK
+type statusError struct {
+	url    string
+	status string
+	code   int
+	// retryAfter is the delay asked by the Retry-After header, 0 if there's
+	// none
+	retryAfter time.Duration
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("%s returned %s", e.url, e.status)
+}
+
+// retryable returns true if a request may succeed if sent again: the server
+// failed, asked to slow down or didn't answer in time
+func retryable(err error) bool {
+	var status statusError
+	if errors.As(err, &status) {
+		return status.code >= 500 || status.code == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// hostFailure returns true if an error means the host isn't working, as
+// opposed to a resource that doesn't exist or a response too large
+func hostFailure(err error) bool {
+	var status statusError
+	if errors.As(err, &status) {
+		return status.code >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// parseRetryAfter parses a Retry-After header, which is a number of seconds
+// or a date
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// backoff returns the delay before a retry, doubling for each attempt with
+// up to half of it random so clients don't retry at the same time
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retryBackoff << attempt
+	if delay > c.maxRetryDelay || delay <= 0 {
+		delay = c.maxRetryDelay
+	}
+	half := int64(delay / 2)
+	return time.Duration(half + c.random(half+1))
+}
+
+// host is the state of the requests sent to a host
+type host struct {
+	// tokens are the requests that can be sent before waiting, refilled at
+	// the rate limit up to the burst size. They go below zero when requests
+	// are waiting for their turn.
+	tokens     float64
+	lastRefill time.Time
+	// failures is the number of requests in a row that failed
+	failures int
+	// unhealthyUntil is when requests are sent again after too many failed
+	unhealthyUntil time.Time
+}
+
+// hosts keeps the state of every host a client sends requests to
+type hosts struct {
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+func (h *hosts) get(name string, c *Client) *host {
+	if h.hosts == nil {
+		h.hosts = map[string]*host{}
+	}
+	state, ok := h.hosts[name]
+	if !ok {
+		state = &host{tokens: float64(c.rateBurst), lastRefill: c.clock.Now()}
+		h.hosts[name] = state
+	}
+	return state
+}
+
+// checkHealth returns ErrUnhealthy if the host failed too many times
+// recently. Once the cooldown is over requests are sent again, and a single
+// failure makes the host unhealthy again.
+func (c *Client) checkHealth(name string) error {
+	if c.breakerFailures < 0 {
+		return nil
+	}
+	c.hosts.mu.Lock()
+	defer c.hosts.mu.Unlock()
+	state := c.hosts.get(name, c)
+	now := c.clock.Now()
+	if now.Before(state.unhealthyUntil) {
+		return fmt.Errorf("%s: %w after %d failed requests, retrying in %s", name, ErrUnhealthy,
+			state.failures, state.unhealthyUntil.Sub(now).Round(time.Second))
+	}
+	return nil
+}
+
+// recordResult updates the health of a host after a request
+func (c *Client) recordResult(name string, err error) {
+	if c.breakerFailures < 0 {
+		return
+	}
+	c.hosts.mu.Lock()
+	defer c.hosts.mu.Unlock()
+	state := c.hosts.get(name, c)
+	if err == nil || !hostFailure(err) {
+		state.failures = 0
+		return
+	}
+	state.failures++
+	if state.failures >= c.breakerFailures {
+		state.unhealthyUntil = c.clock.Now().Add(c.breakerCooldown)
+	}
+}
+
+// waitTurn waits until a request can be sent to a host without going over
+// its rate limit, taking a token from its bucket
+func (c *Client) waitTurn(name string) {
+	if c.rateLimit < 0 {
+		return
+	}
+	c.hosts.mu.Lock()
+	state := c.hosts.get(name, c)
+	now := c.clock.Now()
+	state.tokens += now.Sub(state.lastRefill).Seconds() * c.rateLimit
+	if state.tokens > float64(c.rateBurst) {
+		state.tokens = float64(c.rateBurst)
+	}
+	state.lastRefill = now
+	state.tokens--
+	wait := time.Duration(-state.tokens / c.rateLimit * float64(time.Second))
+	c.hosts.mu.Unlock()
+
+	if wait > 0 {
+		c.clock.Sleep(wait)
+	}
+}
+
+var (
+	randomMu     sync.Mutex
+	randomSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// defaultRandom returns a random number in [0, n)
+func defaultRandom(n int64) int64 {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	return randomSource.Int63n(n)
+}
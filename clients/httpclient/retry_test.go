@@ -0,0 +1,363 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock whose Sleep returns at once, moving the time forward
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+// testServer answers each request with the next status of a list, 200 OK
+// once it runs out, and counts the requests
+type testServer struct {
+	*httptest.Server
+	requests int32
+}
+
+func newTestServer(t *testing.T, headers http.Header, statuses ...int) *testServer {
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&s.requests, 1)) - 1
+		for name, values := range headers {
+			w.Header()[name] = values
+		}
+		if i < len(statuses) {
+			w.WriteHeader(statuses[i])
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) count() int {
+	return int(atomic.LoadInt32(&s.requests))
+}
+
+// newTestClient returns a client with a fake clock and no jitter
+func newTestClient(t *testing.T, config Config) (*Client, *fakeClock) {
+	clock := newFakeClock()
+	config.Clock = clock
+	c, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.random = func(n int64) int64 { return 0 }
+	return c, clock
+}
+
+func TestBackoffBounds(t *testing.T) {
+	c, _ := newTestClient(t, Config{RetryBackoff: 100 * time.Millisecond, MaxRetryDelay: time.Second})
+
+	tests := []struct {
+		attempt int
+		delay   time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{10, time.Second},
+		// the shift overflows
+		{70, time.Second},
+	}
+	for _, tt := range tests {
+		c.random = func(n int64) int64 { return 0 }
+		if got := c.backoff(tt.attempt); got != tt.delay/2 {
+			t.Errorf("backoff(%d) with no jitter = %s, want %s", tt.attempt, got, tt.delay/2)
+		}
+		c.random = func(n int64) int64 { return n - 1 }
+		if got := c.backoff(tt.attempt); got != tt.delay {
+			t.Errorf("backoff(%d) with the most jitter = %s, want %s", tt.attempt, got, tt.delay)
+		}
+	}
+
+	c.random = defaultRandom
+	for i := 0; i < 1000; i++ {
+		attempt := i % 6
+		delay := c.retryBackoff << attempt
+		if delay > c.maxRetryDelay {
+			delay = c.maxRetryDelay
+		}
+		if got := c.backoff(attempt); got < delay/2 || got > delay {
+			t.Fatalf("backoff(%d) = %s, want between %s and %s", attempt, got, delay/2, delay)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := newFakeClock().Now()
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"120", 2 * time.Minute},
+		{"0", 0},
+		{"-3", 0},
+		{"soon", 0},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	s := newTestServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+	c, clock := newTestClient(t, Config{Retries: 2, RetryBackoff: 100 * time.Millisecond})
+
+	body, err := c.Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("body = %q, want ok", body)
+	}
+	if s.count() != 3 {
+		t.Errorf("%d requests sent, want 3", s.count())
+	}
+	want := []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}
+	if !equalDurations(clock.slept, want) {
+		t.Errorf("slept %v, want %v", clock.slept, want)
+	}
+}
+
+func TestRetriesRunOut(t *testing.T) {
+	s := newTestServer(t, nil, 500, 500, 500, 500)
+	c, _ := newTestClient(t, Config{Retries: 2, BreakerFailures: -1})
+
+	_, err := c.Get(s.URL)
+	var status statusError
+	if !errors.As(err, &status) || status.code != 500 {
+		t.Fatalf("err = %v, want a 500 status error", err)
+	}
+	if s.count() != 3 {
+		t.Errorf("%d requests sent, want 3", s.count())
+	}
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	for _, code := range []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusGone} {
+		s := newTestServer(t, nil, code, code)
+		c, clock := newTestClient(t, Config{Retries: 3})
+
+		_, err := c.Get(s.URL)
+		var status statusError
+		if !errors.As(err, &status) || status.code != code {
+			t.Errorf("%d: err = %v, want a status error", code, err)
+		}
+		if s.count() != 1 {
+			t.Errorf("%d: %d requests sent, want 1", code, s.count())
+		}
+		if len(clock.slept) != 0 {
+			t.Errorf("%d: slept %v, want no retries", code, clock.slept)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	s := newTestServer(t, http.Header{"Retry-After": {"3"}}, http.StatusTooManyRequests)
+	c, clock := newTestClient(t, Config{Retries: 1})
+
+	if _, err := c.Get(s.URL); err != nil {
+		t.Fatal(err)
+	}
+	if !equalDurations(clock.slept, []time.Duration{3 * time.Second}) {
+		t.Errorf("slept %v, want [3s]", clock.slept)
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	date := newFakeClock().Now().Add(7 * time.Second).Format(http.TimeFormat)
+	s := newTestServer(t, http.Header{"Retry-After": {date}}, http.StatusServiceUnavailable)
+	c, clock := newTestClient(t, Config{Retries: 1})
+
+	if _, err := c.Get(s.URL); err != nil {
+		t.Fatal(err)
+	}
+	if !equalDurations(clock.slept, []time.Duration{7 * time.Second}) {
+		t.Errorf("slept %v, want [7s]", clock.slept)
+	}
+}
+
+func TestRetryAfterShorterThanBackoff(t *testing.T) {
+	s := newTestServer(t, http.Header{"Retry-After": {"1"}}, http.StatusTooManyRequests)
+	c, clock := newTestClient(t, Config{Retries: 1, RetryBackoff: 4 * time.Second})
+
+	if _, err := c.Get(s.URL); err != nil {
+		t.Fatal(err)
+	}
+	if !equalDurations(clock.slept, []time.Duration{2 * time.Second}) {
+		t.Errorf("slept %v, want the backoff's 2s", clock.slept)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	for _, header := range []string{"60", newFakeClock().Now().Add(time.Hour).Format(http.TimeFormat)} {
+		s := newTestServer(t, http.Header{"Retry-After": {header}}, http.StatusTooManyRequests)
+		c, clock := newTestClient(t, Config{Retries: 3, MaxRetryDelay: 10 * time.Second})
+
+		_, err := c.Get(s.URL)
+		var status statusError
+		if !errors.As(err, &status) || status.code != http.StatusTooManyRequests {
+			t.Errorf("%s: err = %v, want a 429 status error", header, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "retry after") {
+			t.Errorf("%s: err = %v, want the delay asked by the server", header, err)
+		}
+		if s.count() != 1 {
+			t.Errorf("%s: %d requests sent, want 1", header, s.count())
+		}
+		if len(clock.slept) != 0 {
+			t.Errorf("%s: slept %v, want no retries", header, clock.slept)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := newTestServer(t, nil)
+	c, clock := newTestClient(t, Config{RateLimit: 2, RateBurst: 3})
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.Get(s.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the burst is sent at once, then a request every half second
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}
+	if !equalDurations(clock.slept, want) {
+		t.Errorf("slept %v, want %v", clock.slept, want)
+	}
+
+	// the bucket refills while no requests are sent
+	clock.now = clock.now.Add(time.Minute)
+	clock.slept = nil
+	for i := 0; i < 3; i++ {
+		if _, err := c.Get(s.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(clock.slept) != 0 {
+		t.Errorf("slept %v after the bucket refilled, want no waits", clock.slept)
+	}
+}
+
+func TestNoRateLimit(t *testing.T) {
+	s := newTestServer(t, nil)
+	c, clock := newTestClient(t, Config{RateLimit: -1, RateBurst: 1})
+
+	for i := 0; i < 5; i++ {
+		if _, err := c.Get(s.URL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(clock.slept) != 0 {
+		t.Errorf("slept %v, want no waits", clock.slept)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	s := newTestServer(t, nil, 500, 500, 500)
+	c, clock := newTestClient(t, Config{Retries: -1, BreakerFailures: 2, BreakerCooldown: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(s.URL); err == nil || errors.Is(err, ErrUnhealthy) {
+			t.Fatalf("request %d: err = %v, want a status error", i, err)
+		}
+	}
+
+	// open: requests fail without being sent
+	if _, err := c.Get(s.URL); !errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("err = %v, want ErrUnhealthy", err)
+	}
+	if s.count() != 2 {
+		t.Errorf("%d requests sent, want 2", s.count())
+	}
+
+	// half-open: after the cooldown a request is sent, and a single failure
+	// opens the breaker again
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := c.Get(s.URL); err == nil || errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("err = %v after the cooldown, want a status error", err)
+	}
+	if _, err := c.Get(s.URL); !errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("err = %v, want ErrUnhealthy again", err)
+	}
+	if s.count() != 3 {
+		t.Errorf("%d requests sent, want 3", s.count())
+	}
+
+	// closed: a success resets the failures
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := c.Get(s.URL); err != nil {
+		t.Fatalf("err = %v after the second cooldown, want nil", err)
+	}
+	if _, err := c.Get(s.URL); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
+
+func TestBreakerStopsRetries(t *testing.T) {
+	s := newTestServer(t, nil, 500, 500, 500, 500, 500)
+	c, _ := newTestClient(t, Config{Retries: 4, BreakerFailures: 2})
+
+	if _, err := c.Get(s.URL); !errors.Is(err, ErrUnhealthy) {
+		t.Fatalf("err = %v, want ErrUnhealthy", err)
+	}
+	if s.count() != 2 {
+		t.Errorf("%d requests sent, want 2", s.count())
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	s := newTestServer(t, nil, 404, 404, 404, 404)
+	c, _ := newTestClient(t, Config{BreakerFailures: 2})
+
+	for i := 0; i < 4; i++ {
+		if _, err := c.Get(s.URL); errors.Is(err, ErrUnhealthy) {
+			t.Fatalf("request %d: err = %v, want a 404 status error", i, err)
+		}
+	}
+	if s.count() != 4 {
+		t.Errorf("%d requests sent, want 4", s.count())
+	}
+}
+
+func equalDurations(a []time.Duration, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
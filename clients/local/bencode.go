@@ -0,0 +1,159 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var errUnexpectedEnd = errors.New("bencode: unexpected end of data")
+
+// maxNesting is how deep lists and dictionaries can be nested, so that a
+// crafted file can't exhaust the stack
+const maxNesting = 64
+
+// decoder decodes bencoded data into int64, string, []interface{} and
+// map[string]interface{} values. It also records where the top level "info"
+// dictionary is, as the info-hash is the SHA-1 of its raw bytes.
+type decoder struct {
+	data []byte
+	pos  int
+	// depth is the nesting of dictionaries, nesting that of lists and
+	// dictionaries
+	depth     int
+	nesting   int
+	infoStart int
+	infoEnd   int
+}
+
+func decode(data []byte) (interface{}, *decoder, error) {
+	d := &decoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, nil, err
+	}
+	return v, d, nil
+}
+
+// info returns the raw bytes of the top level "info" dictionary
+func (d *decoder) info() []byte {
+	if d.infoEnd == 0 {
+		return nil
+	}
+	return d.data[d.infoStart:d.infoEnd]
+}
+
+func (d *decoder) value() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, errUnexpectedEnd
+	}
+	c := d.data[d.pos]
+	if c == 'l' || c == 'd' {
+		if d.nesting >= maxNesting {
+			return nil, fmt.Errorf("bencode: nested too deeply at %d", d.pos)
+		}
+		d.nesting++
+		defer func() { d.nesting-- }()
+	}
+	switch {
+	case c == 'i':
+		return d.integer()
+	case c == 'l':
+		return d.list()
+	case c == 'd':
+		return d.dict()
+	case c >= '0' && c <= '9':
+		return d.string()
+	default:
+		return nil, fmt.Errorf("bencode: unexpected %q at %d", c, d.pos)
+	}
+}
+
+func (d *decoder) integer() (int64, error) {
+	end := d.find('e')
+	if end < 0 {
+		return 0, errUnexpectedEnd
+	}
+	n, err := strconv.ParseInt(string(d.data[d.pos+1:end]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bencode: invalid integer at %d", d.pos)
+	}
+	d.pos = end + 1
+	return n, nil
+}
+
+func (d *decoder) string() (string, error) {
+	colon := d.find(':')
+	if colon < 0 {
+		return "", errUnexpectedEnd
+	}
+	length, err := strconv.Atoi(string(d.data[d.pos:colon]))
+	if err != nil || length < 0 {
+		return "", fmt.Errorf("bencode: invalid string length at %d", d.pos)
+	}
+	if length > len(d.data)-colon-1 {
+		return "", errUnexpectedEnd
+	}
+	d.pos = colon + 1 + length
+	return string(d.data[colon+1 : d.pos]), nil
+}
+
+func (d *decoder) list() ([]interface{}, error) {
+	d.pos++
+	list := []interface{}{}
+	for {
+		if d.pos >= len(d.data) {
+			return nil, errUnexpectedEnd
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return list, nil
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+}
+
+func (d *decoder) dict() (map[string]interface{}, error) {
+	d.pos++
+	d.depth++
+	defer func() { d.depth-- }()
+
+	dict := map[string]interface{}{}
+	for {
+		if d.pos >= len(d.data) {
+			return nil, errUnexpectedEnd
+		}
+		if d.data[d.pos] == 'e' {
+			d.pos++
+			return dict, nil
+		}
+		key, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+
+		start := d.pos
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		if d.depth == 1 && key == "info" {
+			d.infoStart, d.infoEnd = start, d.pos
+		}
+		dict[key] = v
+	}
+}
+
+// find returns the position of the next c, or -1
+func (d *decoder) find(c byte) int {
+	for i := d.pos; i < len(d.data); i++ {
+		if d.data[i] == c {
+			return i
+		}
+	}
+	return -1
+}
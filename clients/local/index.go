@@ -0,0 +1,129 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/xdg"
+)
+
+// index keeps the metainfo of every .torrent file in a directory, so that
+// only new and changed files are parsed again
+type index struct {
+	Dir     string           `json:"dir"`
+	Entries map[string]entry `json:"entries"`
+}
+
+// entry is a .torrent file in the index, by path relative to the directory
+type entry struct {
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+	// Error is set for files that couldn't be parsed, they aren't parsed
+	// again until they change
+	Error    string   `json:"error,omitempty"`
+	Metainfo metainfo `json:"metainfo"`
+}
+
+// loadIndex reads the index at path. A missing index, or one for another
+// directory, is empty.
+func loadIndex(path string, dir string) (*index, error) {
+	idx := &index{Dir: dir, Entries: map[string]entry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved index
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Dir != dir || saved.Entries == nil {
+		// a corrupt index is rebuilt
+		return idx, nil
+	}
+	return &saved, nil
+}
+
+// clone returns a copy of the index that can be updated
+func (idx *index) clone() *index {
+	c := &index{Dir: idx.Dir, Entries: make(map[string]entry, len(idx.Entries))}
+	for rel, e := range idx.Entries {
+		c.Entries[rel] = e
+	}
+	return c
+}
+
+// update walks the directory, parsing new and changed .torrent files and
+// removing deleted ones. Subdirectories and files that can't be read are
+// skipped. It returns true if the index changed.
+func (idx *index) update() (bool, error) {
+	changed := false
+	found := map[string]bool{}
+
+	err := filepath.WalkDir(idx.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == idx.Dir {
+				return err
+			}
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".torrent") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(idx.Dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		found[rel] = true
+
+		old, ok := idx.Entries[rel]
+		if ok && old.ModTime.Equal(info.ModTime()) && old.Size == info.Size() {
+			return nil
+		}
+
+		e := entry{ModTime: info.ModTime(), Size: info.Size()}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			e.Metainfo, err = parseMetainfo(data)
+		}
+		if err != nil {
+			e.Error = err.Error()
+		}
+		idx.Entries[rel] = e
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for rel := range idx.Entries {
+		if !found[rel] {
+			delete(idx.Entries, rel)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+func (idx *index) save(path string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(path, data)
+}
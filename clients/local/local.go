@@ -0,0 +1,226 @@
+package local
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/ismaelpadilla/gotorrent/xdg"
+	"github.com/skratchdot/open-golang/open"
+)
+
+// DefaultIndexPath returns the location of the index of the library.
+func DefaultIndexPath() string {
+	return filepath.Join(xdg.CacheDir(), "local-index.json")
+}
+
+// refreshInterval is how long the index is used before the directory is
+// walked again
+const refreshInterval = 30 * time.Second
+
+var (
+	librariesMu sync.Mutex
+	// libraries are shared by the clients of the same directory and index,
+	// as a client is created for every search
+	libraries = map[[2]string]*library{}
+)
+
+func New(config Config) interfaces.Client {
+	l := local{dir: config.Dir, indexPath: config.IndexPath}
+	if l.indexPath == "" {
+		l.indexPath = DefaultIndexPath()
+	}
+
+	librariesMu.Lock()
+	defer librariesMu.Unlock()
+	key := [2]string{l.dir, l.indexPath}
+	if libraries[key] == nil {
+		libraries[key] = &library{}
+	}
+	l.library = libraries[key]
+	return l
+}
+
+func (l local) Name() string {
+	return "local"
+}
+
+// Search returns the .torrent files whose name, or the name of one of their
+// files, has every word of the query. The index is updated first if the
+// directory hasn't been walked in the last refreshInterval.
+func (l local) Search(query string) []interfaces.Torrent {
+	idx := l.index()
+
+	words := strings.Fields(strings.ToLower(query))
+	var torrents []interfaces.Torrent
+	for rel, e := range idx.Entries {
+		if e.Error != "" || !matches(e.Metainfo, words) {
+			continue
+		}
+		t := convert(rel, e.Metainfo)
+		t.Client = l
+		torrents = append(torrents, t)
+	}
+	// map iteration order is random
+	sort.Slice(torrents, func(i, j int) bool { return torrents[i].ID < torrents[j].ID })
+	return torrents
+}
+
+// SearchPage returns every result
+func (l local) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	return l.Search(query), ""
+}
+
+// index returns the index of the library, loading it the first time and
+// bringing it up to date with the directory every refreshInterval. The
+// returned index isn't changed afterwards, updates are made on a copy.
+func (l local) index() *index {
+	if l.dir == "" {
+		log.Panic("no directory is configured for the local library")
+	}
+
+	lib := l.library
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	if lib.idx != nil && time.Since(lib.updated) < refreshInterval {
+		return lib.idx
+	}
+
+	var idx *index
+	if lib.idx == nil {
+		var err error
+		idx, err = loadIndex(l.indexPath, l.dir)
+		if err != nil {
+			log.Panic(err)
+		}
+	} else {
+		idx = lib.idx.clone()
+	}
+	changed, err := idx.update()
+	if err != nil {
+		log.Panic(err)
+	}
+	if changed {
+		if err := idx.save(l.indexPath); err != nil {
+			log.Panic(err)
+		}
+	}
+	lib.idx = idx
+	lib.updated = time.Now()
+	return idx
+}
+
+func matches(m metainfo, words []string) bool {
+	name := strings.ToLower(m.Name)
+	for _, word := range words {
+		if strings.Contains(name, word) {
+			continue
+		}
+		found := false
+		for _, f := range m.Files {
+			if strings.Contains(strings.ToLower(f.Path), word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func convert(rel string, m metainfo) interfaces.Torrent {
+	t := interfaces.Torrent{
+		// the ID is the path of the .torrent file in the library
+		ID:          rel,
+		Title:       m.Name,
+		Description: description(m),
+		InfoHash:    m.InfoHash,
+		MagnetLink:  magnetLink(m),
+		Size:        m.Size,
+		Files:       files(m),
+		Release:     release.Parse(m.Name),
+	}
+	if m.CreatedAt > 0 {
+		t.Uploaded = strconv.FormatInt(m.CreatedAt, 10)
+	}
+	return t
+}
+
+func magnetLink(m metainfo) string {
+	link := "magnet:?xt=urn:btih:" + m.InfoHash + "&dn=" + url.QueryEscape(m.Name)
+	for _, tracker := range m.Trackers {
+		link += "&tr=" + url.QueryEscape(tracker)
+	}
+	return link
+}
+
+// description returns the comment of the .torrent file, along with the
+// program that created it, its creation date and its trackers
+func description(m metainfo) string {
+	s := ""
+	if m.CreatedBy != "" {
+		s += "Created by: " + m.CreatedBy + "\n"
+	}
+	if m.CreatedAt > 0 {
+		s += "Created on: " + time.Unix(m.CreatedAt, 0).Format("2006-01-02 15:04") + "\n"
+	}
+	if len(m.Trackers) > 0 {
+		s += "Trackers:\n"
+		for _, tracker := range m.Trackers {
+			s += "  " + tracker + "\n"
+		}
+	}
+	if m.Comment != "" {
+		if s != "" {
+			s += "\n"
+		}
+		s += m.Comment
+	}
+	return s
+}
+
+func files(m metainfo) []interfaces.TorrentFile {
+	files := make([]interfaces.TorrentFile, len(m.Files))
+	for i, f := range m.Files {
+		files[i] = interfaces.TorrentFile{Name: f.Path, Size: f.Size}
+	}
+	return files
+}
+
+// NavigateTo opens the .torrent file, usually in the torrent client
+func (l local) NavigateTo(torrent interfaces.Torrent) {
+	err := open.Run(filepath.Join(l.dir, filepath.FromSlash(torrent.ID)))
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
+func (l local) FetchTorrentDescription(torrent interfaces.Torrent) string {
+	return description(l.metainfo(torrent))
+}
+
+func (l local) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
+	return files(l.metainfo(torrent))
+}
+
+func (l local) metainfo(torrent interfaces.Torrent) metainfo {
+	e, ok := l.index().Entries[torrent.ID]
+	if !ok {
+		log.Panic(fmt.Sprintf("%s is not in the library", torrent.ID))
+	}
+	if e.Error != "" {
+		log.Panic(e.Error)
+	}
+	return e.Metainfo
+}
@@ -0,0 +1,211 @@
+package local
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		data string
+		want interface{}
+	}{
+		{"i42e", int64(42)},
+		{"i-3e", int64(-3)},
+		{"4:spam", "spam"},
+		{"0:", ""},
+		{"l4:spami1ee", []interface{}{"spam", int64(1)}},
+		{"d3:cow3:moo4:spaml1:a1:bee", map[string]interface{}{"cow": "moo", "spam": []interface{}{"a", "b"}}},
+	}
+	for _, tt := range tests {
+		got, _, err := decode([]byte(tt.data))
+		if err != nil {
+			t.Errorf("decode(%q) returned %v", tt.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("decode(%q) = %#v, want %#v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"i42",
+		"ixe",
+		"5:spam",
+		"-1:a",
+		"l4:spam",
+		"d3:cow",
+		"di1e3:cowe",
+		"x",
+	} {
+		if _, _, err := decode([]byte(data)); err == nil {
+			t.Errorf("decode(%q) returned no error", data)
+		}
+	}
+}
+
+func TestDecodeNesting(t *testing.T) {
+	nested := func(n int) []byte {
+		return []byte(strings.Repeat("l", n) + strings.Repeat("e", n))
+	}
+	if _, _, err := decode(nested(maxNesting)); err != nil {
+		t.Errorf("decode of %d nested lists returned %v", maxNesting, err)
+	}
+	if _, _, err := decode(nested(maxNesting + 1)); err == nil {
+		t.Errorf("decode of %d nested lists returned no error", maxNesting+1)
+	}
+	if _, _, err := decode(nested(1 << 20)); err == nil {
+		t.Error("decode of deeply nested lists returned no error")
+	}
+
+	dicts := []byte(strings.Repeat("d1:a", maxNesting+1) + "i1e" + strings.Repeat("e", maxNesting+1))
+	if _, _, err := decode(dicts); err == nil {
+		t.Errorf("decode of %d nested dictionaries returned no error", maxNesting+1)
+	}
+}
+
+// bencoded writes s as a bencoded string
+func bencoded(s string) string {
+	return fmt.Sprintf("%d:%s", len(s), s)
+}
+
+// torrentFile returns a .torrent file with a single file
+func torrentFile(name string, length int) (data []byte, info string) {
+	info = "d6:lengthi" + fmt.Sprint(length) + "e4:name" + bencoded(name) + "12:piece lengthi16384e6:pieces0:e"
+	return []byte("d8:announce" + bencoded("udp://tracker.example:1337") + "4:info" + info + "e"), info
+}
+
+func infoHash(info string) string {
+	hash := sha1.Sum([]byte(info))
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+func TestParseMetainfo(t *testing.T) {
+	data, info := torrentFile("ubuntu.iso", 1024)
+	m, err := parseMetainfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := metainfo{
+		Name:     "ubuntu.iso",
+		InfoHash: infoHash(info),
+		Size:     1024,
+		Files:    []file{{Path: "ubuntu.iso", Size: 1024}},
+		Trackers: []string{"udp://tracker.example:1337"},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("parseMetainfo()\n got %+v\nwant %+v", m, want)
+	}
+}
+
+func TestParseMetainfoFiles(t *testing.T) {
+	info := "d5:filesld6:lengthi10e4:pathl3:sub5:a.txteed6:lengthi5e4:pathl5:b.txteee" +
+		"4:name3:dir10:name.utf-84:dír12:piece lengthi16384e6:pieces0:e"
+	data := "d8:announce" + bencoded("http://a/announce") +
+		"13:announce-listll" + bencoded("http://a/announce") + bencoded("http://b/announce") + "ee" +
+		"7:comment5:hello13:creation datei1700000000e10:created by4:tool" +
+		"4:info" + info + "e"
+
+	m, err := parseMetainfo([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := metainfo{
+		Name:      "dír",
+		InfoHash:  infoHash(info),
+		Size:      15,
+		Files:     []file{{Path: "dír/sub/a.txt", Size: 10}, {Path: "dír/b.txt", Size: 5}},
+		Trackers:  []string{"http://a/announce", "http://b/announce"},
+		CreatedAt: 1700000000,
+		CreatedBy: "tool",
+		Comment:   "hello",
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("parseMetainfo()\n got %+v\nwant %+v", m, want)
+	}
+}
+
+func TestParseMetainfoNotTorrent(t *testing.T) {
+	for _, data := range []string{"i1e", "de", "d4:infoi1ee"} {
+		if _, err := parseMetainfo([]byte(data)); err != errNotTorrent {
+			t.Errorf("parseMetainfo(%q) returned %v, want %v", data, err, errNotTorrent)
+		}
+	}
+}
+
+func writeTorrent(t *testing.T, path string, name string) {
+	t.Helper()
+	data, _ := torrentFile(name, 1)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func titles(l local, query string) []string {
+	var titles []string
+	for _, torrent := range l.Search(query) {
+		titles = append(titles, torrent.Title)
+	}
+	return titles
+}
+
+func TestSearchKeepsIndex(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	writeTorrent(t, filepath.Join(dir, "a.torrent"), "ubuntu desktop")
+	l := New(Config{Dir: dir, IndexPath: indexPath}).(local)
+
+	if got := titles(l, "ubuntu"); !reflect.DeepEqual(got, []string{"ubuntu desktop"}) {
+		t.Fatalf("results = %q", got)
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("the index wasn't saved: %v", err)
+	}
+
+	// the directory isn't walked again until the index is old
+	writeTorrent(t, filepath.Join(dir, "sub", "b.torrent"), "ubuntu server")
+	if got := titles(l, "ubuntu"); len(got) != 1 {
+		t.Errorf("results = %q, want those of the kept index", got)
+	}
+	// new clients share the index
+	l = New(Config{Dir: dir, IndexPath: indexPath}).(local)
+	l.library.updated = time.Now().Add(-refreshInterval)
+	if got := titles(l, "ubuntu"); !reflect.DeepEqual(got, []string{"ubuntu desktop", "ubuntu server"}) {
+		t.Errorf("results = %q, want both after a refresh", got)
+	}
+
+	if description := l.FetchTorrentDescription(l.Search("server")[0]); !strings.Contains(description, "tracker.example") {
+		t.Errorf("description = %q, want the trackers", description)
+	}
+}
+
+func TestSearchSkipsUnreadableDirectories(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("every directory can be read by root")
+	}
+	dir := t.TempDir()
+	writeTorrent(t, filepath.Join(dir, "a.torrent"), "ubuntu desktop")
+	writeTorrent(t, filepath.Join(dir, "private", "b.torrent"), "ubuntu server")
+	if err := os.Chmod(filepath.Join(dir, "private"), 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(filepath.Join(dir, "private"), 0o700)
+
+	l := New(Config{Dir: dir, IndexPath: filepath.Join(t.TempDir(), "index.json")}).(local)
+	if got := titles(l, "ubuntu"); !reflect.DeepEqual(got, []string{"ubuntu desktop"}) {
+		t.Errorf("results = %q, want the readable torrent", got)
+	}
+}
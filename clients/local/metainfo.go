@@ -0,0 +1,123 @@
+package local
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"path"
+	"strings"
+)
+
+// metainfo is what is read from a .torrent file
+type metainfo struct {
+	Name      string   `json:"name"`
+	InfoHash  string   `json:"info_hash"`
+	Size      int      `json:"size"`
+	Files     []file   `json:"files,omitempty"`
+	Trackers  []string `json:"trackers,omitempty"`
+	CreatedAt int64    `json:"created_at,omitempty"`
+	CreatedBy string   `json:"created_by,omitempty"`
+	Comment   string   `json:"comment,omitempty"`
+}
+
+type file struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+}
+
+var errNotTorrent = errors.New("not a .torrent file")
+
+// parseMetainfo reads a .torrent file
+func parseMetainfo(data []byte) (metainfo, error) {
+	v, d, err := decode(data)
+	if err != nil {
+		return metainfo{}, err
+	}
+	root, ok := v.(map[string]interface{})
+	if !ok {
+		return metainfo{}, errNotTorrent
+	}
+	info, ok := root["info"].(map[string]interface{})
+	if !ok {
+		return metainfo{}, errNotTorrent
+	}
+
+	hash := sha1.Sum(d.info())
+	m := metainfo{
+		Name:      text(info, "name"),
+		InfoHash:  strings.ToUpper(hex.EncodeToString(hash[:])),
+		CreatedAt: integer(root, "creation date"),
+		CreatedBy: text(root, "created by"),
+		Comment:   text(root, "comment"),
+		Trackers:  trackers(root),
+	}
+
+	files, isList := info["files"].([]interface{})
+	if !isList {
+		m.Size = int(integer(info, "length"))
+		m.Files = []file{{Path: m.Name, Size: m.Size}}
+		return m, nil
+	}
+	for _, f := range files {
+		f, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		parts := []string{m.Name}
+		filePath := list(f, "path.utf-8")
+		if len(filePath) == 0 {
+			filePath = list(f, "path")
+		}
+		for _, p := range filePath {
+			if p, ok := p.(string); ok {
+				parts = append(parts, p)
+			}
+		}
+		size := int(integer(f, "length"))
+		m.Files = append(m.Files, file{Path: path.Join(parts...), Size: size})
+		m.Size += size
+	}
+	return m, nil
+}
+
+// trackers returns the announce URL and the ones in announce-list, without
+// duplicates
+func trackers(root map[string]interface{}) []string {
+	var found []string
+	seen := map[string]bool{}
+	add := func(v interface{}) {
+		if tracker, ok := v.(string); ok && tracker != "" && !seen[tracker] {
+			seen[tracker] = true
+			found = append(found, tracker)
+		}
+	}
+
+	add(root["announce"])
+	for _, tier := range list(root, "announce-list") {
+		if tier, ok := tier.([]interface{}); ok {
+			for _, tracker := range tier {
+				add(tracker)
+			}
+		}
+	}
+	return found
+}
+
+// text returns a string value, preferring its UTF-8 version if there is one
+func text(dict map[string]interface{}, key string) string {
+	if s, ok := dict[key+".utf-8"].(string); ok {
+		return s
+	}
+	s, _ := dict[key].(string)
+	return s
+}
+
+func integer(dict map[string]interface{}, key string) int64 {
+	n, _ := dict[key].(int64)
+	return n
+}
+
+func list(dict map[string]interface{}, key string) []interface{} {
+	l, _ := dict[key].([]interface{})
+	return l
+}
@@ -0,0 +1,30 @@
+package local
+
+import (
+	"sync"
+	"time"
+)
+
+type local struct {
+	dir       string
+	indexPath string
+	library   *library
+}
+
+// library is the index of a directory, kept in memory between searches
+type library struct {
+	// mu keeps searches from updating the index at the same time
+	mu  sync.Mutex
+	idx *index
+	// updated is when the directory was last walked
+	updated time.Time
+}
+
+// Config holds the options of the local library client.
+type Config struct {
+	// Dir is the directory with the .torrent files, searched recursively
+	Dir string
+	// IndexPath is where the index of the .torrent files is kept,
+	// DefaultIndexPath() if empty
+	IndexPath string
+}
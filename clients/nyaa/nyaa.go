@@ -0,0 +1,253 @@
+package nyaa
+
+import (
+	"encoding/xml"
+	"html"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/query"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const baseURL = "https://nyaa.si"
+
+func New(web *httpclient.Client) interfaces.Client {
+	return nyaa{web: web}
+}
+
+func (n nyaa) Name() string {
+	return "nyaa"
+}
+
+func (n nyaa) Search(query string) []interfaces.Torrent {
+	return n.search(query, "0_0")
+}
+
+// SearchQuery searches in the query's category when it's a single one of
+// Nyaa's categories
+func (n nyaa) SearchQuery(q query.Query, page string) ([]interfaces.Torrent, string) {
+	if len(q.Categories) == 0 {
+		return n.search(q.Text, "0_0"), ""
+	}
+	codes := searchCategories(q.Categories)
+	switch len(codes) {
+	case 0:
+		// Nyaa has none of the categories
+		return nil, ""
+	case 1:
+		return n.search(q.Text, codes[0]), ""
+	default:
+		return n.search(q.Text, "0_0"), ""
+	}
+}
+
+func (n nyaa) search(query string, category string) []interfaces.Torrent {
+	body := n.get(baseURL + "/?page=rss&c=" + category + "&f=0&q=" + url.QueryEscape(query))
+
+	var feed nyaaFeed
+	err := xml.Unmarshal(body, &feed)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	torrents := make([]interfaces.Torrent, len(feed.Items))
+	for i, item := range feed.Items {
+		torrents[i] = item.convert()
+		torrents[i].Client = n
+	}
+	return torrents
+}
+
+// Latest returns the latest torrents in every category
+func (n nyaa) Latest() []interfaces.Torrent {
+	return n.search("", "0_0")
+}
+
+// SearchPage returns every result, Nyaa's RSS feed isn't paginated
+func (n nyaa) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	return n.Search(query), ""
+}
+
+func (i nyaaItem) convert() interfaces.Torrent {
+	t := interfaces.Torrent{
+		// the guid is the torrent's page, https://nyaa.si/view/<id>
+		ID:         path.Base(i.GUID),
+		Title:      i.Title,
+		InfoHash:   i.InfoHash,
+		MagnetLink: "magnet:?xt=urn:btih:" + i.InfoHash + "&dn=" + url.QueryEscape(i.Title),
+		Size:       parseSize(i.Size),
+		Seeders:    i.Seeders,
+		Leechers:   i.Leechers,
+		Category:   convertCategory(i.CategoryID),
+		Release:    release.Parse(i.Title),
+	}
+	if added, err := time.Parse(time.RFC1123Z, i.PubDate); err == nil {
+		t.Uploaded = strconv.FormatInt(added.Unix(), 10)
+	}
+
+	// remakes are reuploads of other releases, they take precedence over the
+	// trusted flag as Nyaa shows them in red
+	switch {
+	case i.Remake == "Yes":
+		t.UploaderStatus = "remake"
+	case i.Trusted == "Yes":
+		t.UploaderStatus = "trusted"
+	}
+	return t
+}
+
+// Nyaa's categories, and the equivalent Torznab categories. Subcategories
+// are mapped by their parent except where Torznab has a closer match.
+var categories = map[string]interfaces.Category{
+	"1": interfaces.CategoryTVAnime,
+	"2": interfaces.CategoryAudio,
+	"3": interfaces.CategoryBooks,
+	"4": interfaces.CategoryTV,
+	"5": interfaces.CategoryOther,
+	"6": interfaces.CategoryPC,
+
+	"3_1": interfaces.CategoryEBooks,
+	"6_2": interfaces.CategoryPCGames,
+}
+
+// convertCategory converts a category id such as "1_2" (Anime -
+// English-translated)
+func convertCategory(category string) interfaces.Category {
+	if c, ok := categories[category]; ok {
+		return c
+	}
+	parent, _, _ := strings.Cut(category, "_")
+	if c, ok := categories[parent]; ok {
+		return c
+	}
+	return interfaces.CategoryUnknown
+}
+
+// searchCategories returns the ids of Nyaa's categories that are any of the
+// given categories, such as "1_0" for anime, leaving out subcategories of
+// the ones included
+func searchCategories(searched []interfaces.Category) []string {
+	included := map[string]bool{}
+	for id, c := range categories {
+		for _, s := range searched {
+			if c.Matches(s) {
+				included[id] = true
+			}
+		}
+	}
+	var ids []string
+	for id := range included {
+		parent, _, isSubcategory := strings.Cut(id, "_")
+		if isSubcategory && included[parent] {
+			continue
+		}
+		if !isSubcategory {
+			id += "_0"
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+var sizeUnits = map[string]float64{
+	"Bytes": 1,
+	"KiB":   1 << 10,
+	"MiB":   1 << 20,
+	"GiB":   1 << 30,
+	"TiB":   1 << 40,
+}
+
+// parseSize parses sizes such as "1.4 GiB", returning 0 if it's not valid
+func parseSize(size string) int {
+	value, unit, ok := strings.Cut(strings.TrimSpace(size), " ")
+	if !ok {
+		return 0
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return int(number * sizeUnits[unit])
+}
+
+func (n nyaa) NavigateTo(torrent interfaces.Torrent) {
+	err := open.Run(baseURL + "/view/" + torrent.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
+var descriptionRegexp = regexp.MustCompile(`(?s)<div[^>]*id="torrent-description"[^>]*>(.*?)</div>`)
+
+// FetchTorrentDescription returns the torrent's description as written by
+// the uploader, usually markdown
+func (n nyaa) FetchTorrentDescription(torrent interfaces.Torrent) string {
+	return parseDescription(n.get(baseURL + "/view/" + torrent.ID))
+}
+
+// parseDescription reads the description of a torrent's page
+func parseDescription(page []byte) string {
+	match := descriptionRegexp.FindSubmatch(page)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(html.UnescapeString(string(match[1])))
+}
+
+// matches, in order, folders, files and the end of a folder in the file list
+var fileListRegexp = regexp.MustCompile(`(?s)class="folder"[^>]*>\s*<i[^>]*></i>([^<]*)</a>` +
+	`|<i class="fa fa-file"></i>([^<]*)<span class="file-size">\(([^)]*)\)</span>` +
+	`|</ul>`)
+
+func (n nyaa) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
+	return parseFiles(string(n.get(baseURL + "/view/" + torrent.ID)))
+}
+
+// parseFiles reads the file list of a torrent's page, where files are nested
+// in their folders
+func parseFiles(page string) []interfaces.TorrentFile {
+	start := strings.Index(page, "torrent-file-list")
+	if start < 0 {
+		return nil
+	}
+
+	var files []interfaces.TorrentFile
+	var folders []string
+	for _, match := range fileListRegexp.FindAllStringSubmatch(page[start:], -1) {
+		switch {
+		case match[1] != "":
+			folders = append(folders, strings.TrimSpace(html.UnescapeString(match[1])))
+		case match[2] != "":
+			name := strings.TrimSpace(html.UnescapeString(match[2]))
+			files = append(files, interfaces.TorrentFile{
+				Name: path.Join(append(folders, name)...),
+				Size: parseSize(match[3]),
+			})
+		case len(folders) == 0:
+			// end of the file list
+			return files
+		default:
+			folders = folders[:len(folders)-1]
+		}
+	}
+	return files
+}
+
+func (n nyaa) get(url string) []byte {
+	body, err := n.web.Get(url)
+	if err != nil {
+		log.Panic(err)
+	}
+	return body
+}
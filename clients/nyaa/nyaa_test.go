@@ -0,0 +1,179 @@
+package nyaa
+
+import (
+	"encoding/xml"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+func readFeed(t *testing.T) []nyaaItem {
+	data, err := os.ReadFile("testdata/search.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed nyaaFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatal(err)
+	}
+	return feed.Items
+}
+
+func TestConvert(t *testing.T) {
+	items := readFeed(t)
+	if len(items) != 4 {
+		t.Fatalf("read %d items, want 4", len(items))
+	}
+
+	got := items[0].convert()
+	want := interfaces.Torrent{
+		ID:             "1780001",
+		Title:          "[SubsPlease] Sousou no Frieren - 28 (1080p) [A1B2C3D4].mkv",
+		InfoHash:       "0123456789abcdef0123456789abcdef01234567",
+		MagnetLink:     "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=%5BSubsPlease%5D+Sousou+no+Frieren+-+28+%281080p%29+%5BA1B2C3D4%5D.mkv",
+		Size:           1503238553,
+		Uploaded:       "1711123272",
+		UploaderStatus: "trusted",
+		Seeders:        1523,
+		Leechers:       87,
+		Category:       interfaces.CategoryTVAnime,
+	}
+	got.Release = want.Release
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convert()\n got %+v\nwant %+v", got, want)
+	}
+	if release := items[0].convert().Release; release.Group != "SubsPlease" || release.Episode != 28 || release.Resolution != "1080p" {
+		t.Errorf("release = %+v, want SubsPlease's episode 28 in 1080p", release)
+	}
+}
+
+func TestConvertCategoriesAndRemakes(t *testing.T) {
+	tests := []struct {
+		category interfaces.Category
+		status   string
+		uploaded string
+		size     int
+	}{
+		{interfaces.CategoryTVAnime, "trusted", "1711123272", 1503238553},
+		{interfaces.CategoryEBooks, "", "1711009800", 89443532},
+		// remakes take precedence over the trusted flag, and invalid dates
+		// are left empty
+		{interfaces.CategoryTVAnime, "remake", "", 12 << 40},
+		{interfaces.CategoryPCGames, "", "1710979199", 512},
+	}
+	for i, item := range readFeed(t) {
+		got := item.convert()
+		tt := tests[i]
+		if got.Category != tt.category {
+			t.Errorf("%s: category = %s, want %s", got.Title, got.Category, tt.category)
+		}
+		if got.UploaderStatus != tt.status {
+			t.Errorf("%s: uploader status = %q, want %q", got.Title, got.UploaderStatus, tt.status)
+		}
+		if got.Uploaded != tt.uploaded {
+			t.Errorf("%s: uploaded = %q, want %q", got.Title, got.Uploaded, tt.uploaded)
+		}
+		if got.Size != tt.size {
+			t.Errorf("%s: size = %d, want %d", got.Title, got.Size, tt.size)
+		}
+	}
+}
+
+func TestConvertCategory(t *testing.T) {
+	tests := map[string]interfaces.Category{
+		"1_2": interfaces.CategoryTVAnime,
+		"1_0": interfaces.CategoryTVAnime,
+		"2_1": interfaces.CategoryAudio,
+		"3_1": interfaces.CategoryEBooks,
+		"3_3": interfaces.CategoryBooks,
+		"4_1": interfaces.CategoryTV,
+		"5_2": interfaces.CategoryOther,
+		"6_1": interfaces.CategoryPC,
+		"6_2": interfaces.CategoryPCGames,
+		"7_1": interfaces.CategoryUnknown,
+		"":    interfaces.CategoryUnknown,
+	}
+	for id, want := range tests {
+		if got := convertCategory(id); got != want {
+			t.Errorf("convertCategory(%q) = %s, want %s", id, got, want)
+		}
+	}
+}
+
+func TestSearchCategories(t *testing.T) {
+	tests := []struct {
+		categories []interfaces.Category
+		want       []string
+	}{
+		{[]interfaces.Category{interfaces.CategoryTVAnime}, []string{"1_0"}},
+		{[]interfaces.Category{interfaces.CategoryPCGames}, []string{"6_2"}},
+		// subcategories are left out when their parent is included
+		{[]interfaces.Category{interfaces.CategoryBooks}, []string{"3_0"}},
+		{[]interfaces.Category{interfaces.CategoryEBooks, interfaces.CategoryBooks}, []string{"3_0"}},
+		{[]interfaces.Category{interfaces.CategoryAudio, interfaces.CategoryPC}, []string{"2_0", "6_0"}},
+		{[]interfaces.Category{interfaces.CategoryXXX}, nil},
+	}
+	for _, tt := range tests {
+		if got := searchCategories(tt.categories); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchCategories(%v) = %v, want %v", tt.categories, got, tt.want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int{
+		"1.4 GiB":    1503238553,
+		"512 Bytes":  512,
+		"512.0 KiB":  512 << 10,
+		"85.3 MiB":   89443532,
+		"2 TiB":      2 << 40,
+		" 3 MiB ":    3 << 20,
+		"":           0,
+		"1.4GiB":     0,
+		"lots GiB":   0,
+		"1.4 Blocks": 0,
+	}
+	for size, want := range tests {
+		if got := parseSize(size); got != want {
+			t.Errorf("parseSize(%q) = %d, want %d", size, got, want)
+		}
+	}
+}
+
+func readPage(t *testing.T) []byte {
+	page, err := os.ReadFile("testdata/view.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestParseDescription(t *testing.T) {
+	want := "**Sousou no Frieren** episode 28\n\nEncoded by [SubsPlease](https://subsplease.org) & friends <3"
+	if got := parseDescription(readPage(t)); got != want {
+		t.Errorf("parseDescription() = %q, want %q", got, want)
+	}
+	if got := parseDescription([]byte("<html></html>")); got != "" {
+		t.Errorf("parseDescription() of a page without description = %q, want none", got)
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	want := []interfaces.TorrentFile{
+		{Name: "Frieren/[SubsPlease] Sousou no Frieren - 28 (1080p).mkv", Size: 1503238553},
+		{Name: "Frieren/Extras & Fonts/font.ttf", Size: 512 << 10},
+		{Name: "Frieren/notes.txt", Size: 12},
+		{Name: "README.txt", Size: 1 << 10},
+	}
+	got := parseFiles(string(readPage(t)))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFiles()\n got %+v\nwant %+v", got, want)
+	}
+
+	if files := parseFiles(strings.Replace(string(readPage(t)), "torrent-file-list", "", 1)); files != nil {
+		t.Errorf("parseFiles() of a page without file list = %+v, want none", files)
+	}
+}
@@ -0,0 +1,29 @@
+package nyaa
+
+import "github.com/ismaelpadilla/gotorrent/clients/httpclient"
+
+type nyaa struct {
+	web *httpclient.Client
+}
+
+type nyaaFeed struct {
+	Items []nyaaItem `xml:"channel>item"`
+}
+
+// nyaaItem is an item of Nyaa's RSS feed, fields without a namespace
+// are in the https://nyaa.si/xmlns/nyaa namespace
+type nyaaItem struct {
+	Title      string `xml:"title"`
+	Link       string `xml:"link"`
+	GUID       string `xml:"guid"`
+	PubDate    string `xml:"pubDate"`
+	Seeders    int    `xml:"seeders"`
+	Leechers   int    `xml:"leechers"`
+	Downloads  int    `xml:"downloads"`
+	InfoHash   string `xml:"infoHash"`
+	CategoryID string `xml:"categoryId"`
+	Category   string `xml:"category"`
+	Size       string `xml:"size"`
+	Trusted    string `xml:"trusted"`
+	Remake     string `xml:"remake"`
+}
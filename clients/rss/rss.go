@@ -0,0 +1,328 @@
+package rss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/skratchdot/open-golang/open"
+)
+
+// elements used for each field when a feed doesn't map it, in order of
+// preference. Torznab attributes are available by their name.
+var defaultFields = map[string][]string{
+	"title":       {"title"},
+	"link":        {"comments", "link", "guid", "id"},
+	"description": {"description", "summary", "content"},
+	"date":        {"pubDate", "published", "updated", "date"},
+	"size":        {"size", "contentLength", "enclosure@length"},
+	"seeders":     {"seeders", "seeds"},
+	"leechers":    {"leechers", "peers"},
+	"infohash":    {"infohash", "infoHash"},
+	"magnet":      {"magneturl", "magnetURI", "magnet"},
+	"category":    {"category"},
+	"torrent":     {"enclosure@url", "link"},
+}
+
+var hashRegexp = regexp.MustCompile(`(?i)urn:btih:([0-9a-f]{40})`)
+
+// New returns a client that searches every feed. Its name is "rss" when there
+// are several feeds, and "rss:<name>" for a single one.
+func New(feeds []Feed, web *httpclient.Client) interfaces.Client {
+	name := "rss"
+	if len(feeds) == 1 {
+		name = "rss:" + feeds[0].Name
+	}
+	return client{name: name, feeds: feeds, web: web}
+}
+
+func (c client) Name() string {
+	return c.name
+}
+
+// Search fetches every feed and returns the items whose titles have every
+// word of the query. Feeds can't be searched, so only their current items
+// are found.
+func (c client) Search(query string) []interfaces.Torrent {
+	if len(c.feeds) == 0 {
+		log.Panic("no RSS feeds are configured")
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	var torrents []interfaces.Torrent
+	for _, feed := range c.feeds {
+		for _, i := range feed.items(c.web) {
+			t := feed.convert(i)
+			if !containsAll(strings.ToLower(t.Title), words) {
+				continue
+			}
+			t.Client = c
+			torrents = append(torrents, t)
+		}
+	}
+	return torrents
+}
+
+// Latest returns every item of the feeds
+func (c client) Latest() []interfaces.Torrent {
+	return c.Search("")
+}
+
+// SearchPage returns every result, feeds aren't paginated
+func (c client) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	return c.Search(query), ""
+}
+
+func containsAll(title string, words []string) bool {
+	for _, word := range words {
+		if !strings.Contains(title, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// field returns the value of the item's element mapped to name
+func (f Feed) field(i item, name string) string {
+	if element, ok := f.Fields[name]; ok {
+		return i[element]
+	}
+	for _, element := range defaultFields[name] {
+		if value := i[element]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func (f Feed) convert(i item) interfaces.Torrent {
+	title := f.field(i, "title")
+	t := interfaces.Torrent{
+		ID:          f.field(i, "link"),
+		Title:       title,
+		Description: f.field(i, "description"),
+		InfoHash:    strings.ToUpper(f.field(i, "infohash")),
+		MagnetLink:  torrentLink(f.field(i, "magnet")),
+		Size:        parseSize(f.field(i, "size")),
+		Release:     release.Parse(title),
+	}
+	t.Seeders, _ = strconv.Atoi(f.field(i, "seeders"))
+	t.Leechers, _ = strconv.Atoi(f.field(i, "leechers"))
+	if category, err := strconv.Atoi(f.field(i, "category")); err == nil {
+		t.Category = interfaces.Category(category)
+	}
+	if uploaded, ok := parseDate(f.field(i, "date")); ok {
+		t.Uploaded = strconv.FormatInt(uploaded.Unix(), 10)
+	}
+
+	if t.MagnetLink == "" {
+		if torrent := torrentLink(f.field(i, "torrent")); strings.HasPrefix(strings.ToLower(torrent), "magnet:") {
+			t.MagnetLink = torrent
+		}
+	}
+	if t.InfoHash == "" {
+		if m := hashRegexp.FindStringSubmatch(t.MagnetLink); m != nil {
+			t.InfoHash = strings.ToUpper(m[1])
+		}
+	}
+	switch {
+	case t.MagnetLink == "" && t.InfoHash != "":
+		t.MagnetLink = "magnet:?xt=urn:btih:" + t.InfoHash + "&dn=" + url.QueryEscape(title)
+	case t.MagnetLink == "":
+		// feeds of private trackers usually only have .torrent files,
+		// opening the link downloads it
+		t.MagnetLink = torrentLink(f.field(i, "torrent"))
+	}
+	return t
+}
+
+// torrentLink returns link if it's a magnet link or a web address, and ""
+// otherwise. Torrent links are opened and copied, so feeds can't be allowed
+// to use file: or other schemes.
+func torrentLink(link string) string {
+	if strings.HasPrefix(strings.ToLower(link), "magnet:?") || httpclient.WebLink(link) {
+		return link
+	}
+	return ""
+}
+
+var sizeUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+	"TB":  1 << 40,
+	"TIB": 1 << 40,
+}
+
+var sizeRegexp = regexp.MustCompile(`^([\d.,]+)\s*([A-Za-z]*)$`)
+
+// parseSize parses a number of bytes or sizes such as "1.4 GB", returning 0
+// if it's not valid
+func parseSize(size string) int {
+	m := sizeRegexp.FindStringSubmatch(strings.TrimSpace(size))
+	if m == nil {
+		return 0
+	}
+	number, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return int(number * sizeUnits[strings.ToUpper(m[2])])
+}
+
+var dateLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700"}
+
+func parseDate(date string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(date)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (f Feed) items(web *httpclient.Client) []item {
+	request, err := http.NewRequest(http.MethodGet, f.URL, nil)
+	if err != nil {
+		log.Panic(err)
+	}
+	if f.Cookie != "" {
+		request.Header.Set("Cookie", f.Cookie)
+	}
+	for name, value := range f.Headers {
+		request.Header.Set(name, value)
+	}
+
+	body, err := web.Do(request)
+	if err != nil {
+		log.Panic(fmt.Sprintf("%s: %v", f.Name, err))
+	}
+
+	items, err := parseItems(bytes.NewReader(body))
+	if err != nil {
+		log.Panic(fmt.Sprintf("%s: %v", f.Name, err))
+	}
+	return items
+}
+
+// parseItems reads the items of an RSS feed or the entries of an Atom feed.
+// Elements are stored by their name without namespace, and attributes as
+// element@attribute. Torznab and Newznab attributes are stored by their
+// name. Only the first value of repeated elements is kept.
+func parseItems(r io.Reader) ([]item, error) {
+	decoder := xml.NewDecoder(r)
+	// feeds aren't always UTF-8, their text is read as is
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	var items []item
+	var current item
+	var element string
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			name := token.Name.Local
+			if name == "item" || name == "entry" {
+				current = item{}
+				continue
+			}
+			if current == nil {
+				continue
+			}
+			element = name
+			text.Reset()
+			addAttributes(current, name, token.Attr)
+		case xml.CharData:
+			if current != nil && element != "" {
+				text.Write(token)
+			}
+		case xml.EndElement:
+			name := token.Name.Local
+			switch {
+			case current == nil:
+			case name == "item" || name == "entry":
+				items = append(items, current)
+				current = nil
+			case name == element:
+				set(current, name, strings.TrimSpace(text.String()))
+				element = ""
+			}
+		}
+	}
+}
+
+func addAttributes(i item, element string, attributes []xml.Attr) {
+	values := make(map[string]string, len(attributes))
+	for _, a := range attributes {
+		values[a.Name.Local] = a.Value
+	}
+
+	switch {
+	case element == "attr" && values["name"] != "":
+		set(i, values["name"], values["value"])
+	case element == "link" && values["rel"] == "enclosure":
+		// Atom's enclosures are links
+		set(i, "enclosure@url", values["href"])
+		set(i, "enclosure@length", values["length"])
+	case element == "link" && values["href"] != "":
+		set(i, "link", values["href"])
+	}
+	for name, value := range values {
+		set(i, element+"@"+name, value)
+	}
+}
+
+// set stores the value of an element, unless it was already found
+func set(i item, name string, value string) {
+	if value == "" || i[name] != "" {
+		return
+	}
+	i[name] = value
+}
+
+func (c client) NavigateTo(torrent interfaces.Torrent) {
+	if !httpclient.WebLink(torrent.ID) {
+		log.Panic("the torrent has no page")
+	}
+	err := open.Run(torrent.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
+// FetchTorrentDescription returns an empty description, descriptions are
+// read along with the rest of the item
+func (c client) FetchTorrentDescription(torrent interfaces.Torrent) string {
+	return ""
+}
+
+// FetchTorrentFiles returns no files, feeds don't list them
+func (c client) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
+	return []interfaces.TorrentFile{}
+}
@@ -0,0 +1,163 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+func readItems(t *testing.T, name string) []item {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	items, err := parseItems(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestConvertTorznab(t *testing.T) {
+	items := readItems(t, "torznab.xml")
+	if len(items) != 3 {
+		t.Fatalf("read %d items, want 3", len(items))
+	}
+
+	got := Feed{}.convert(items[0])
+	want := interfaces.Torrent{
+		ID:          "https://tracker.example/details/101",
+		Title:       "Show.Name.S01E02.1080p.WEB-DL.x264-GROUP",
+		Description: "Episode <b>two</b>",
+		InfoHash:    "0123456789ABCDEF0123456789ABCDEF01234567",
+		MagnetLink:  "magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567&dn=Show.Name.S01E02.1080p.WEB-DL.x264-GROUP",
+		Size:        1503238553,
+		Uploaded:    "1711207272",
+		Seeders:     25,
+		Leechers:    30,
+		Category:    interfaces.CategoryTVHD,
+	}
+	got.Release = want.Release
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convert()\n got %+v\nwant %+v", got, want)
+	}
+
+	// the info-hash is read from the magnet link
+	movie := Feed{}.convert(items[1])
+	if movie.InfoHash != "89ABCDEF0123456789ABCDEF0123456789ABCDEF" || movie.Size != 1503238553 || movie.Uploaded != "" {
+		t.Errorf("convert() = %+v", movie)
+	}
+
+	// without magnet link or info-hash the .torrent file is opened
+	private := Feed{}.convert(items[2])
+	if private.MagnetLink != "https://tracker.example/download/103.torrent?passkey=abc" || private.InfoHash != "" {
+		t.Errorf("convert() = %+v, want the .torrent link", private)
+	}
+}
+
+func TestConvertAtom(t *testing.T) {
+	items := readItems(t, "atom.xml")
+	if len(items) != 1 {
+		t.Fatalf("read %d items, want 1", len(items))
+	}
+	got := Feed{}.convert(items[0])
+	if got.ID != "https://atom.example/t/7" || got.MagnetLink != "https://atom.example/t/7.torrent" ||
+		got.Size != 4096 || got.Description != "Lossless" || got.Uploaded != "1710928800" {
+		t.Errorf("convert() = %+v", got)
+	}
+}
+
+func TestConvertFields(t *testing.T) {
+	items := readItems(t, "torznab.xml")
+	feed := Feed{Fields: map[string]string{"link": "guid", "size": "enclosure@length", "seeders": "peers"}}
+	got := feed.convert(items[0])
+	if got.ID != "https://tracker.example/torrent/101" || got.Seeders != 30 {
+		t.Errorf("convert() with mapped fields = %+v", got)
+	}
+}
+
+func TestConvertDropsOtherSchemes(t *testing.T) {
+	for _, i := range readItems(t, "hostile.xml") {
+		got := Feed{}.convert(i)
+		switch got.MagnetLink {
+		case "", "magnet:?xt=urn:btih:FEDCBA9876543210FEDCBA9876543210FEDCBA98&dn=script+magnet+with+hash":
+		default:
+			t.Errorf("%s: magnet link = %q, want none", got.Title, got.MagnetLink)
+		}
+	}
+}
+
+func TestTorrentLink(t *testing.T) {
+	tests := map[string]string{
+		"magnet:?xt=urn:btih:abc":           "magnet:?xt=urn:btih:abc",
+		"MAGNET:?xt=urn:btih:abc":           "MAGNET:?xt=urn:btih:abc",
+		"https://tracker.example/1.torrent": "https://tracker.example/1.torrent",
+		"http://tracker.example/1":          "http://tracker.example/1",
+		"file:///etc/passwd":                "",
+		"javascript:alert(1)":               "",
+		"steam://run/1":                     "",
+		"https:///no-host":                  "",
+		"magnet":                            "",
+		"":                                  "",
+	}
+	for link, want := range tests {
+		if got := torrentLink(link); got != want {
+			t.Errorf("torrentLink(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int{
+		"1024":        1024,
+		"1.5 KB":      1536,
+		"2 GiB":       2 << 30,
+		"1,024 MB":    1 << 30,
+		"":            0,
+		"big":         0,
+		"1.4 parsecs": 0,
+	}
+	for size, want := range tests {
+		if got := parseSize(size); got != want {
+			t.Errorf("parseSize(%q) = %d, want %d", size, got, want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	feed, err := os.ReadFile("testdata/torznab.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "uid=1" || r.Header.Get("X-Passkey") != "abc" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write(feed)
+	}))
+	defer s.Close()
+	web, err := httpclient.New(httpclient.Config{Retries: -1, RateLimit: -1, BreakerFailures: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := New([]Feed{{Name: "tracker", URL: s.URL, Cookie: "uid=1", Headers: map[string]string{"X-Passkey": "abc"}}}, web)
+	if c.Name() != "rss:tracker" {
+		t.Errorf("name = %q", c.Name())
+	}
+	torrents := c.Search("movie 720P")
+	if len(torrents) != 1 || torrents[0].Title != "Movie Title 2023 720p" || torrents[0].Client == nil {
+		t.Errorf("results = %+v, want the movie", torrents)
+	}
+	if latest := c.(client).Latest(); len(latest) != 3 {
+		t.Errorf("latest = %d torrents, want 3", len(latest))
+	}
+}
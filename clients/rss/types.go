@@ -0,0 +1,27 @@
+package rss
+
+import "github.com/ismaelpadilla/gotorrent/clients/httpclient"
+
+// Feed is an RSS or Atom feed of torrents, defined in the config file.
+type Feed struct {
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	// Cookie is sent with every request, for trackers that need a login
+	Cookie string `mapstructure:"cookie"`
+	// Headers are sent with every request, such as a passkey header
+	Headers map[string]string `mapstructure:"headers"`
+	// Fields maps the torrent fields (title, link, description, date, size,
+	// seeders, leechers, infohash, magnet and category) to the item's
+	// elements. Attributes are written as element@attribute, such as
+	// enclosure@length. Fields not mapped use common element names.
+	Fields map[string]string `mapstructure:"fields"`
+}
+
+type client struct {
+	name  string
+	feeds []Feed
+	web   *httpclient.Client
+}
+
+// item holds the elements of a feed's item by name, see parseItems
+type item map[string]string
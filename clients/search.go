@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/query"
+)
+
+// SearchError is returned when a provider fails to search.
+type SearchError struct {
+	Provider string
+	Err      error
+}
+
+func (e SearchError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Provider, e.Err)
+}
+
+func (e SearchError) Unwrap() error {
+	return e.Err
+}
+
+// Search runs the query in every client at the same time, and returns all
+// results in the order of the clients. Clients that fail are skipped and
+// their errors returned.
+func Search(clients []interfaces.Client, query string) ([]interfaces.Torrent, []error) {
+	return search(clients, func(c interfaces.Client) []interfaces.Torrent {
+		return c.Search(query)
+	})
+}
+
+// SearchQuery runs a parsed query in every client at the same time, like
+// Search. Only the first page of each client's results is returned.
+func SearchQuery(clients []interfaces.Client, q query.Query) ([]interfaces.Torrent, []error) {
+	return search(clients, func(c interfaces.Client) []interfaces.Torrent {
+		torrents, _ := query.SearchPage(c, q, "")
+		return torrents
+	})
+}
+
+// Lister is implemented by clients that can list the latest torrents of
+// their provider, without a query.
+type Lister interface {
+	Latest() []interfaces.Torrent
+}
+
+// Latest lists the latest torrents of every client at the same time, like
+// Search. Clients that can't list them return no torrents.
+func Latest(clients []interfaces.Client) ([]interfaces.Torrent, []error) {
+	return search(clients, func(c interfaces.Client) []interfaces.Torrent {
+		if lister, ok := c.(Lister); ok {
+			return lister.Latest()
+		}
+		return nil
+	})
+}
+
+func search(clients []interfaces.Client, searchClient func(interfaces.Client) []interfaces.Torrent) ([]interfaces.Torrent, []error) {
+	results := make([][]interfaces.Torrent, len(clients))
+	errs := make([]error, len(clients))
+
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c interfaces.Client) {
+			defer wg.Done()
+			err := Recover(func() {
+				results[i] = searchClient(c)
+			})
+			if err != nil {
+				errs[i] = SearchError{c.Name(), err}
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	var torrents []interfaces.Torrent
+	for _, r := range results {
+		torrents = append(torrents, r...)
+	}
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return torrents, failed
+}
+
+// Recover runs f and returns the value of a panic in it as an error. Clients
+// panic when a request fails, this gets the failure back.
+func Recover(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	f()
+	return nil
+}
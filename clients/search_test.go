@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// fakeClient finds a torrent named after the client, or panics like clients
+// do when a request fails
+type fakeClient struct {
+	name string
+	fail bool
+}
+
+func (c fakeClient) Name() string { return c.name }
+
+func (c fakeClient) Search(query string) []interfaces.Torrent {
+	if c.fail {
+		panic("network error")
+	}
+	return []interfaces.Torrent{{Title: c.name + " " + query}}
+}
+
+func (c fakeClient) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	return c.Search(query), ""
+}
+
+func (fakeClient) NavigateTo(interfaces.Torrent) {}
+
+func (fakeClient) FetchTorrentDescription(interfaces.Torrent) string { return "" }
+
+func (fakeClient) FetchTorrentFiles(interfaces.Torrent) []interfaces.TorrentFile { return nil }
+
+func TestRecover(t *testing.T) {
+	if err := Recover(func() {}); err != nil {
+		t.Errorf("Recover() = %v, want no error", err)
+	}
+	if err := Recover(func() { panic("network error") }); err == nil || err.Error() != "network error" {
+		t.Errorf("Recover() = %v, want the panic", err)
+	}
+	if err := Recover(func() { panic(errors.New("timeout")) }); err == nil || err.Error() != "timeout" {
+		t.Errorf("Recover() = %v, want the panic", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	providers := []interfaces.Client{fakeClient{name: "a"}, fakeClient{name: "b", fail: true}, fakeClient{name: "c"}}
+	torrents, errs := Search(providers, "ubuntu")
+
+	if len(torrents) != 2 || torrents[0].Title != "a ubuntu" || torrents[1].Title != "c ubuntu" {
+		t.Errorf("torrents = %+v, want the results of a and c in order", torrents)
+	}
+	var searchErr SearchError
+	if len(errs) != 1 || !errors.As(errs[0], &searchErr) || searchErr.Provider != "b" {
+		t.Fatalf("errors = %v, want the failure of b", errs)
+	}
+	if got := errs[0].Error(); got != "b: network error" {
+		t.Errorf("error = %q, want %q", got, "b: network error")
+	}
+}
@@ -2,33 +2,51 @@ package thepiratebay
 
 import (
 	"encoding/json"
-	"io"
 	"log"
-	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
 	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/query"
+	"github.com/ismaelpadilla/gotorrent/release"
 	"github.com/skratchdot/open-golang/open"
 )
 
-func New() interfaces.Client {
-	return pirateBay{}
+const apiURL = "https://apibay.org"
+
+func New(web *httpclient.Client) interfaces.Client {
+	return pirateBay{web: web}
+}
+
+func (p pirateBay) Name() string {
+	return "tpb"
 }
 
 func (p pirateBay) Search(a string) []interfaces.Torrent {
-	url := "https://apibay.org/q.php?q=" + a
-	result, err := http.Get(url)
-	if err != nil {
-		log.Panic(err)
-	}
-	defer result.Body.Close()
+	return p.search(url.Values{"q": {a}})
+}
 
-	body, err := io.ReadAll(result.Body)
-	if err != nil {
-		log.Panic(err)
+// SearchQuery searches in the query's categories, if it has any
+func (p pirateBay) SearchQuery(q query.Query, page string) ([]interfaces.Torrent, string) {
+	params := url.Values{"q": {q.Text}}
+	if len(q.Categories) > 0 {
+		codes := searchCategories(q.Categories)
+		if len(codes) == 0 {
+			// ThePirateBay has none of the categories
+			return nil, ""
+		}
+		params.Set("cat", strings.Join(codes, ","))
 	}
+	return p.search(params), ""
+}
+
+func (p pirateBay) search(params url.Values) []interfaces.Torrent {
+	body := p.get("/q.php", params)
 	var bodyParsed []pirateBayTorrent
-	err = json.Unmarshal(body, &bodyParsed)
+	err := json.Unmarshal(body, &bodyParsed)
 	if err != nil {
 		log.Panic(err)
 	}
@@ -41,6 +59,11 @@ func (p pirateBay) Search(a string) []interfaces.Torrent {
 	return torrents
 }
 
+// SearchPage returns every result, ThePirateBay's API doesn't paginate
+func (p pirateBay) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	return p.Search(query), ""
+}
+
 func (p pirateBayTorrent) convert() interfaces.Torrent {
 	magnetLink := "magnet:?xt=urn:btih:" + p.InfoHash
 	size, err := strconv.Atoi(p.Size)
@@ -57,15 +80,79 @@ func (p pirateBayTorrent) convert() interfaces.Torrent {
 	}
 
 	return interfaces.Torrent{
-		ID:         p.ID,
-		Title:      p.Name,
-		InfoHash:   p.InfoHash,
-		MagnetLink: magnetLink,
-		Size:       size,
-		Uploaded:   p.Added,
-		Seeders:    seeders,
-		Leechers:   leechers,
+		ID:             p.ID,
+		Title:          p.Name,
+		InfoHash:       p.InfoHash,
+		MagnetLink:     magnetLink,
+		Size:           size,
+		Uploaded:       p.Added,
+		Uploader:       p.Username,
+		UploaderStatus: p.Status,
+		Seeders:        seeders,
+		Leechers:       leechers,
+		Category:       convertCategory(p.Category),
+		Release:        release.Parse(p.Name),
+	}
+}
+
+// ThePirateBay's categories, and the equivalent Torznab categories
+var categories = map[string]interfaces.Category{
+	"100": interfaces.CategoryAudio,
+	"101": interfaces.CategoryAudioMP3,
+	"102": interfaces.CategoryAudioBook,
+	"200": interfaces.CategoryMovies,
+	"201": interfaces.CategoryMoviesSD,
+	"202": interfaces.CategoryMoviesSD,
+	"203": interfaces.CategoryAudioVid,
+	"205": interfaces.CategoryTVSD,
+	"207": interfaces.CategoryMoviesHD,
+	"208": interfaces.CategoryTVHD,
+	"209": interfaces.CategoryMovies3D,
+	"211": interfaces.CategoryMovies4K,
+	"212": interfaces.CategoryTV4K,
+	"300": interfaces.CategoryPC,
+	"400": interfaces.CategoryConsole,
+	"401": interfaces.CategoryPCGames,
+	"500": interfaces.CategoryXXX,
+	"600": interfaces.CategoryOther,
+	"601": interfaces.CategoryEBooks,
+	"602": interfaces.CategoryComics,
+}
+
+func convertCategory(category string) interfaces.Category {
+	if c, ok := categories[category]; ok {
+		return c
+	}
+	// fall back to the top level category, "304" becomes "300"
+	if len(category) == 3 {
+		if c, ok := categories[category[:1]+"00"]; ok {
+			return c
+		}
+	}
+	return interfaces.CategoryUnknown
+}
+
+// searchCategories returns the codes of ThePirateBay's categories that are
+// any of the given categories, leaving out subcategories of the ones
+// included, so movies are "200" rather than "200,201,202,207,209,211"
+func searchCategories(searched []interfaces.Category) []string {
+	included := map[string]bool{}
+	for code, c := range categories {
+		for _, s := range searched {
+			if c.Matches(s) {
+				included[code] = true
+			}
+		}
+	}
+	var codes []string
+	for code := range included {
+		if code[1:] != "00" && included[code[:1]+"00"] {
+			continue
+		}
+		codes = append(codes, code)
 	}
+	sort.Strings(codes)
+	return codes
 }
 
 func (p pirateBay) NavigateTo(torrent interfaces.Torrent) {
@@ -77,19 +164,9 @@ func (p pirateBay) NavigateTo(torrent interfaces.Torrent) {
 }
 
 func (p pirateBay) FetchTorrentDescription(torrent interfaces.Torrent) string {
-	url := "https://apibay.org/t.php?id=" + torrent.ID
-	result, err := http.Get(url)
-	if err != nil {
-		log.Panic(err)
-	}
-	defer result.Body.Close()
-
-	body, err := io.ReadAll(result.Body)
-	if err != nil {
-		log.Panic(err)
-	}
+	body := p.get("/t.php", url.Values{"id": {torrent.ID}})
 	var bodyParsed pirateBayTorrentDetails
-	err = json.Unmarshal(body, &bodyParsed)
+	err := json.Unmarshal(body, &bodyParsed)
 	if err != nil {
 		log.Panic("cant unmarshall", err)
 	}
@@ -98,19 +175,9 @@ func (p pirateBay) FetchTorrentDescription(torrent interfaces.Torrent) string {
 }
 
 func (p pirateBay) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
-	url := "https://apibay.org/f.php?id=" + torrent.ID
-	result, err := http.Get(url)
-	if err != nil {
-		log.Panic(err)
-	}
-	defer result.Body.Close()
-
-	body, err := io.ReadAll(result.Body)
-	if err != nil {
-		log.Panic(err)
-	}
+	body := p.get("/f.php", url.Values{"id": {torrent.ID}})
 	var bodyParsed []pirateBayTorrentFile
-	err = json.Unmarshal(body, &bodyParsed)
+	err := json.Unmarshal(body, &bodyParsed)
 	if err != nil {
 		log.Panic("cant unmarshall", err)
 	}
@@ -125,6 +192,15 @@ func (p pirateBay) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.To
 	return torrentFiles
 }
 
+// get fetches an API endpoint, such as /q.php
+func (p pirateBay) get(path string, params url.Values) []byte {
+	body, err := p.web.Get(apiURL + path + "?" + params.Encode())
+	if err != nil {
+		log.Panic(err)
+	}
+	return body
+}
+
 // get a valid proxy
 func (p pirateBay) getProxy() string {
 	// TODO
@@ -1,6 +1,10 @@
 package thepiratebay
 
-type pirateBay struct{}
+import "github.com/ismaelpadilla/gotorrent/clients/httpclient"
+
+type pirateBay struct {
+	web *httpclient.Client
+}
 
 type pirateBayTorrent struct {
 	ID       string
@@ -11,6 +15,9 @@ type pirateBayTorrent struct {
 	Seeders  string
 	Size     string
 	Added    string
+	Username string
+	Status   string
+	Category string
 }
 
 type pirateBayTorrentDetails struct {
@@ -0,0 +1,43 @@
+package x1337
+
+import (
+	"sync"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+)
+
+type x1337 struct {
+	mirrors *mirrors
+	pages   int
+	web     *httpclient.Client
+}
+
+// mirrors are tried in order until one of them answers, the one that
+// answered is moved to the front. They are shared by the copies of the
+// client stored in its torrents.
+type mirrors struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+// Config holds the options of the 1337x client.
+type Config struct {
+	// Mirrors are the base URLs of the 1337x mirrors to use, such as
+	// https://1337x.to
+	Mirrors []string
+	// Pages is the number of result pages fetched for each search
+	Pages int
+}
+
+// result is a row of a search results page
+type result struct {
+	path          string
+	title         string
+	seeders       int
+	leechers      int
+	date          string
+	size          string
+	uploader      string
+	uploaderClass string
+	category      string
+}
@@ -0,0 +1,480 @@
+package x1337
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/skratchdot/open-golang/open"
+)
+
+// DefaultMirrors are used when no mirrors are configured
+var DefaultMirrors = []string{
+	"https://1337x.to",
+	"https://1337x.st",
+	"https://x1337x.ws",
+	"https://x1337x.eu",
+}
+
+// number of detail pages fetched at the same time
+const detailWorkers = 5
+
+func New(config Config, web *httpclient.Client) interfaces.Client {
+	urls := DefaultMirrors
+	if len(config.Mirrors) > 0 {
+		urls = config.Mirrors
+	}
+	x := x1337{mirrors: &mirrors{}, pages: 1, web: web}
+	for _, u := range urls {
+		x.mirrors.urls = append(x.mirrors.urls, strings.TrimSuffix(u, "/"))
+	}
+	if config.Pages > 0 {
+		x.pages = config.Pages
+	}
+	return x
+}
+
+func (x x1337) Name() string {
+	return "1337x"
+}
+
+func (x x1337) Search(query string) []interfaces.Torrent {
+	torrents, _ := x.SearchPage(query, "")
+	return torrents
+}
+
+// SearchPage fetches the configured number of result pages, starting at the
+// page number in page. Result pages don't include info-hashes, so the
+// detail page of every result is fetched too.
+func (x x1337) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	first, err := strconv.Atoi(page)
+	if err != nil || first < 1 {
+		first = 1
+	}
+
+	var results []result
+	next := ""
+	for number := first; number < first+x.pages; number++ {
+		body, err := x.get("/search/" + url.PathEscape(query) + "/" + strconv.Itoa(number) + "/")
+		if err != nil {
+			log.Panic(err)
+		}
+		page := string(body)
+		results = append(results, parseResults(page)...)
+		if !hasNextPage(page) {
+			next = ""
+			break
+		}
+		next = strconv.Itoa(number + 1)
+	}
+	return x.details(results), next
+}
+
+// details fetches the detail page of every result to convert them
+func (x x1337) details(results []result) []interfaces.Torrent {
+	torrents := make([]interfaces.Torrent, len(results))
+	errs := make([]error, len(results))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < detailWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				torrents[i], errs[i] = x.convert(results[i])
+			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// results whose detail page couldn't be fetched have no magnet link,
+	// they are dropped
+	found := torrents[:0]
+	var failed error
+	for i, t := range torrents {
+		if errs[i] != nil {
+			failed = errs[i]
+			continue
+		}
+		found = append(found, t)
+	}
+	if len(found) == 0 && failed != nil {
+		log.Panic(failed)
+	}
+	return found
+}
+
+func (x x1337) convert(r result) (interfaces.Torrent, error) {
+	body, err := x.get(r.path)
+	if err != nil {
+		return interfaces.Torrent{}, err
+	}
+	magnetLink := parseMagnetLink(string(body))
+	if magnetLink == "" {
+		return interfaces.Torrent{}, fmt.Errorf("no magnet link in %s", r.path)
+	}
+
+	t := interfaces.Torrent{
+		Client: x,
+		// the ID is the path of the detail page without the /torrent/
+		// prefix, such as 5123456/Some-Name/
+		ID:             strings.TrimPrefix(r.path, "/torrent/"),
+		Title:          r.title,
+		InfoHash:       infoHash(magnetLink),
+		MagnetLink:     magnetLink,
+		Size:           parseSize(r.size),
+		Uploader:       r.uploader,
+		UploaderStatus: uploaderStatus(r.uploaderClass),
+		Seeders:        r.seeders,
+		Leechers:       r.leechers,
+		Category:       convertCategory(r.category),
+		Release:        release.Parse(r.title),
+	}
+	if uploaded, ok := parseDate(r.date, time.Now()); ok {
+		t.Uploaded = strconv.FormatInt(uploaded.Unix(), 10)
+	}
+	return t, nil
+}
+
+var (
+	rowRegexp      = regexp.MustCompile(`(?s)<tr>\s*<td class="coll-1 name">(.*?)</tr>`)
+	nameRegexp     = regexp.MustCompile(`<a href="(/torrent/[^"]+)">([^<]*)</a>`)
+	categoryRegexp = regexp.MustCompile(`<a href="/sub/(\d+)/`)
+	seedsRegexp    = regexp.MustCompile(`<td class="coll-2 seeds">(\d+)</td>`)
+	leechesRegexp  = regexp.MustCompile(`<td class="coll-3 leeches">(\d+)</td>`)
+	dateRegexp     = regexp.MustCompile(`<td class="coll-date">([^<]*)</td>`)
+	sizeRegexp     = regexp.MustCompile(`<td class="coll-4 size[^"]*">([^<]*)<`)
+	uploaderRegexp = regexp.MustCompile(`(?s)<td class="coll-5 ([^"]*)">(?:<a[^>]*>)?([^<]*)`)
+)
+
+// parseResults reads the rows of a search results page
+func parseResults(page string) []result {
+	var results []result
+	for _, row := range rowRegexp.FindAllStringSubmatch(page, -1) {
+		name := nameRegexp.FindStringSubmatch(row[1])
+		if name == nil {
+			continue
+		}
+		r := result{
+			path:  name[1],
+			title: strings.TrimSpace(html.UnescapeString(name[2])),
+		}
+		if m := categoryRegexp.FindStringSubmatch(row[1]); m != nil {
+			r.category = m[1]
+		}
+		if m := seedsRegexp.FindStringSubmatch(row[1]); m != nil {
+			r.seeders, _ = strconv.Atoi(m[1])
+		}
+		if m := leechesRegexp.FindStringSubmatch(row[1]); m != nil {
+			r.leechers, _ = strconv.Atoi(m[1])
+		}
+		if m := dateRegexp.FindStringSubmatch(row[1]); m != nil {
+			r.date = strings.TrimSpace(m[1])
+		}
+		if m := sizeRegexp.FindStringSubmatch(row[1]); m != nil {
+			r.size = strings.TrimSpace(m[1])
+		}
+		if m := uploaderRegexp.FindStringSubmatch(row[1]); m != nil {
+			r.uploaderClass = m[1]
+			r.uploader = strings.TrimSpace(html.UnescapeString(m[2]))
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// hasNextPage returns true if the pagination of a search results page has a
+// page after the active one
+func hasNextPage(page string) bool {
+	start := strings.Index(page, `<div class="pagination">`)
+	if start < 0 {
+		return false
+	}
+	pagination := page[start:]
+	if end := strings.Index(pagination, "</ul>"); end >= 0 {
+		pagination = pagination[:end]
+	}
+	active := strings.Index(pagination, `class="active"`)
+	if active < 0 {
+		return false
+	}
+	// the active page's item has a link too
+	rest := pagination[active:]
+	if end := strings.Index(rest, "</li>"); end >= 0 {
+		rest = rest[end:]
+	}
+	return strings.Contains(rest, "<a ")
+}
+
+// uploaderStatus converts the class of the uploader column
+func uploaderStatus(class string) string {
+	switch class {
+	case "vip":
+		return "vip"
+	case "uploader", "trial-uploader":
+		return "trusted"
+	default:
+		return ""
+	}
+}
+
+// 1337x's subcategories, and the equivalent Torznab categories
+var categories = map[string]interfaces.Category{
+	// movies
+	"1":  interfaces.CategoryMoviesSD,
+	"2":  interfaces.CategoryMoviesSD,
+	"3":  interfaces.CategoryMoviesSD,
+	"4":  interfaces.CategoryMovies,
+	"42": interfaces.CategoryMoviesHD,
+	"54": interfaces.CategoryMoviesHD,
+	"55": interfaces.CategoryMovies,
+	"66": interfaces.CategoryMovies3D,
+	"70": interfaces.CategoryMovies4K,
+	"73": interfaces.CategoryMovies,
+	"76": interfaces.CategoryMovies4K,
+	// TV
+	"5":  interfaces.CategoryTVSD,
+	"6":  interfaces.CategoryTVSD,
+	"7":  interfaces.CategoryTVSD,
+	"9":  interfaces.CategoryTV,
+	"41": interfaces.CategoryTVHD,
+	"71": interfaces.CategoryTVHD,
+	"74": interfaces.CategoryTV,
+	"75": interfaces.CategoryTVSD,
+	// games
+	"10": interfaces.CategoryPCGames,
+	"11": interfaces.CategoryConsole,
+	"12": interfaces.CategoryConsole,
+	"13": interfaces.CategoryConsole,
+	"14": interfaces.CategoryConsole,
+	"15": interfaces.CategoryConsole,
+	"16": interfaces.CategoryConsole,
+	"17": interfaces.CategoryConsole,
+	"43": interfaces.CategoryConsole,
+	"44": interfaces.CategoryConsole,
+	"45": interfaces.CategoryConsole,
+	"46": interfaces.CategoryConsole,
+	"72": interfaces.CategoryConsole,
+	"77": interfaces.CategoryConsole,
+	"82": interfaces.CategoryConsole,
+	// music
+	"22": interfaces.CategoryAudioMP3,
+	"23": interfaces.CategoryAudio,
+	"24": interfaces.CategoryAudioVid,
+	"25": interfaces.CategoryAudioVid,
+	"26": interfaces.CategoryAudio,
+	"27": interfaces.CategoryAudio,
+	"53": interfaces.CategoryAudio,
+	// applications
+	"18": interfaces.CategoryPC,
+	"19": interfaces.CategoryPC,
+	"20": interfaces.CategoryPC,
+	"21": interfaces.CategoryPC,
+	"56": interfaces.CategoryPC,
+	// anime
+	"28": interfaces.CategoryTVAnime,
+	"78": interfaces.CategoryTVAnime,
+	"79": interfaces.CategoryTVAnime,
+	"80": interfaces.CategoryTVAnime,
+	"81": interfaces.CategoryTVAnime,
+	// other
+	"36": interfaces.CategoryEBooks,
+	"39": interfaces.CategoryComics,
+	"52": interfaces.CategoryAudioBook,
+	// XXX
+	"48": interfaces.CategoryXXX,
+	"49": interfaces.CategoryXXX,
+	"50": interfaces.CategoryXXX,
+	"51": interfaces.CategoryXXX,
+}
+
+func convertCategory(category string) interfaces.Category {
+	if c, ok := categories[category]; ok {
+		return c
+	}
+	return interfaces.CategoryUnknown
+}
+
+var sizeUnits = map[string]float64{
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// parseSize parses sizes such as "1.4 GB", returning 0 if it's not valid.
+// 1337x uses binary units.
+func parseSize(size string) int {
+	value, unit, ok := strings.Cut(strings.TrimSpace(size), " ")
+	if !ok {
+		return 0
+	}
+	number, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return int(number * sizeUnits[unit])
+}
+
+var ordinalRegexp = regexp.MustCompile(`(\d+)(st|nd|rd|th)`)
+
+// parseDate parses the dates of the search results, such as "Jan. 5th '24"
+// for older torrents and "3am" for the ones uploaded today
+func parseDate(date string, now time.Time) (time.Time, bool) {
+	if t, err := time.ParseInLocation("3pm", date, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), 0, 0, 0, now.Location()), true
+	}
+
+	date = strings.ReplaceAll(date, ".", "")
+	date = ordinalRegexp.ReplaceAllString(date, "$1")
+	t, err := time.ParseInLocation("Jan 2 '06", date, now.Location())
+	return t, err == nil
+}
+
+func (x x1337) NavigateTo(torrent interfaces.Torrent) {
+	err := open.Run(x.mirrors.list()[0] + "/torrent/" + torrent.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
+var (
+	magnetRegexp     = regexp.MustCompile(`href="(magnet:\?[^"]+)"`)
+	hashRegexp       = regexp.MustCompile(`(?i)urn:btih:([0-9a-f]{40})`)
+	tagRegexp        = regexp.MustCompile(`<[^>]*>`)
+	lineBreakRegexp  = regexp.MustCompile(`(?i)<br\s*/?>|</p>`)
+	blankLinesRegexp = regexp.MustCompile(`\n{3,}`)
+	fileRegexp       = regexp.MustCompile(`<li><i class="flaticon-[^"]*"></i>\s*([^<]+?)\s*<span class="head">\s*\(([^)]*)\)\s*</span>`)
+)
+
+// markers of the description and files tabs of a detail page
+const (
+	descriptionStart = `id="description"`
+	descriptionEnd   = `id="files"`
+	filesStart       = `class="file-content"`
+)
+
+var errAllMirrorsDown = errors.New("every 1337x mirror failed")
+
+func parseMagnetLink(page string) string {
+	m := magnetRegexp.FindStringSubmatch(page)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1])
+}
+
+func infoHash(magnetLink string) string {
+	m := hashRegexp.FindStringSubmatch(magnetLink)
+	if m == nil {
+		return ""
+	}
+	return strings.ToUpper(m[1])
+}
+
+func (x x1337) FetchTorrentDescription(torrent interfaces.Torrent) string {
+	body, err := x.get("/torrent/" + torrent.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+	return parseDescription(string(body))
+}
+
+// parseDescription returns the text of the description tab of a detail page
+func parseDescription(page string) string {
+	start := strings.Index(page, descriptionStart)
+	if start < 0 {
+		return ""
+	}
+	page = page[start:]
+	// skip the rest of the opening tag
+	if gt := strings.Index(page, ">"); gt >= 0 {
+		page = page[gt+1:]
+	}
+	if end := strings.Index(page, descriptionEnd); end >= 0 {
+		// cut before the tag of the files tab, if it's a tag
+		if lt := strings.LastIndex(page[:end], "<"); lt >= 0 {
+			end = lt
+		}
+		page = page[:end]
+	}
+
+	text := lineBreakRegexp.ReplaceAllString(page, "\n")
+	text = tagRegexp.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	text = strings.ReplaceAll(text, "\r", "")
+	return strings.TrimSpace(blankLinesRegexp.ReplaceAllString(text, "\n\n"))
+}
+
+func (x x1337) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
+	body, err := x.get("/torrent/" + torrent.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+	return parseFiles(string(body))
+}
+
+// parseFiles reads the files tab of a detail page
+func parseFiles(page string) []interfaces.TorrentFile {
+	start := strings.Index(page, filesStart)
+	if start < 0 {
+		return nil
+	}
+
+	var files []interfaces.TorrentFile
+	for _, m := range fileRegexp.FindAllStringSubmatch(page[start:], -1) {
+		files = append(files, interfaces.TorrentFile{
+			Name: html.UnescapeString(m[1]),
+			Size: parseSize(m[2]),
+		})
+	}
+	return files
+}
+
+// get fetches path from the first mirror that answers
+func (x x1337) get(path string) ([]byte, error) {
+	var errs []string
+	for _, mirror := range x.mirrors.list() {
+		body, err := x.web.Get(mirror + path)
+		if err == nil {
+			x.mirrors.promote(mirror)
+			return body, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return nil, fmt.Errorf("%w: %s", errAllMirrorsDown, strings.Join(errs, "; "))
+}
+
+func (m *mirrors) list() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.urls...)
+}
+
+// promote moves mirror to the front, so the next requests go to it first
+func (m *mirrors) promote(mirror string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, u := range m.urls {
+		if u == mirror {
+			copy(m.urls[1:i+1], m.urls[:i])
+			m.urls[0] = mirror
+			return
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package x1337
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func readPage(t *testing.T, name string) string {
+	t.Helper()
+	page, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(page)
+}
+
+// checkGolden compares got with the golden file testdata/<name>.golden, or
+// replaces the file with -update
+func checkGolden(t *testing.T, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s doesn't match %s, run go test -update if the change is expected\n got:\n%s\nwant:\n%s", name, path, got, want)
+	}
+}
+
+func TestParseResults(t *testing.T) {
+	var b strings.Builder
+	for _, r := range parseResults(readPage(t, "search.html")) {
+		fmt.Fprintf(&b, "%+v\n", r)
+	}
+	checkGolden(t, "search", b.String())
+}
+
+func TestParseDescription(t *testing.T) {
+	checkGolden(t, "description", parseDescription(readPage(t, "torrent.html"))+"\n")
+
+	// the end marker isn't preceded by a tag
+	if got := parseDescription(readPage(t, "torrent-minimal.html")); got != "No description," {
+		t.Errorf("parseDescription() = %q, want the text before the marker", got)
+	}
+	if got := parseDescription(`<div id="description">`); got != "" {
+		t.Errorf("parseDescription() of an empty tab = %q, want none", got)
+	}
+	if got := parseDescription("<html></html>"); got != "" {
+		t.Errorf("parseDescription() of a page without description = %q, want none", got)
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	var b strings.Builder
+	for _, f := range parseFiles(readPage(t, "torrent.html")) {
+		fmt.Fprintf(&b, "%s\t%d\n", f.Name, f.Size)
+	}
+	checkGolden(t, "files", b.String())
+
+	if files := parseFiles(readPage(t, "torrent-minimal.html")); files != nil {
+		t.Errorf("parseFiles() of a page without files = %+v, want none", files)
+	}
+}
+
+func TestParseMagnetLink(t *testing.T) {
+	magnetLink := parseMagnetLink(readPage(t, "torrent.html"))
+	want := "magnet:?xt=urn:btih:3b245504cf5f11bbdbe1201cea6a6bf45aee1bc0&dn=Ubuntu+22.04.3+Desktop+amd64&tr=udp%3A%2F%2Ftracker.opentrackr.org%3A1337%2Fannounce"
+	if magnetLink != want {
+		t.Errorf("parseMagnetLink() = %q, want %q", magnetLink, want)
+	}
+	if hash := infoHash(magnetLink); hash != "3B245504CF5F11BBDBE1201CEA6A6BF45AEE1BC0" {
+		t.Errorf("infoHash() = %q", hash)
+	}
+	if got := parseMagnetLink("<html></html>"); got != "" {
+		t.Errorf("parseMagnetLink() of a page without magnet link = %q, want none", got)
+	}
+}
+
+func TestHasNextPage(t *testing.T) {
+	if !hasNextPage(readPage(t, "search.html")) {
+		t.Error("the first page has no next page")
+	}
+	if hasNextPage(readPage(t, "search-last.html")) {
+		t.Error("the last page has a next page")
+	}
+	if hasNextPage("<html></html>") {
+		t.Error("a page without pagination has a next page")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int{
+		"4.7 GB":     5046586572,
+		"1,536.5 MB": 1611137024,
+		"300 KB":     300 << 10,
+		"1,024 B":    1024,
+		"2 TB":       2 << 40,
+		"":           0,
+		"4.7GB":      0,
+		"big GB":     0,
+	}
+	for size, want := range tests {
+		if got := parseSize(size); got != want {
+			t.Errorf("parseSize(%q) = %d, want %d", size, got, want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		date string
+		want time.Time
+		ok   bool
+	}{
+		{"3am", time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC), true},
+		{"11pm", time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC), true},
+		{"Jan. 5th '24", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), true},
+		{"Dec. 31st '23", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), true},
+		{"Mar. 2nd '22", time.Date(2022, 3, 2, 0, 0, 0, 0, time.UTC), true},
+		{"Aug. 23rd '21", time.Date(2021, 8, 23, 0, 0, 0, 0, time.UTC), true},
+		{"May 1st '20", time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC), true},
+		{"yesterday", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDate(tt.date, now)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %s, %t, want %s, %t", tt.date, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestUploaderStatus(t *testing.T) {
+	tests := map[string]string{
+		"vip":            "vip",
+		"uploader":       "trusted",
+		"trial-uploader": "trusted",
+		"user":           "",
+		"":               "",
+	}
+	for class, want := range tests {
+		if got := uploaderStatus(class); got != want {
+			t.Errorf("uploaderStatus(%q) = %q, want %q", class, got, want)
+		}
+	}
+}
+
+// newTestMirror serves the stored pages like a 1337x mirror
+func newTestMirror(t *testing.T) *httptest.Server {
+	pages := map[string]string{
+		"/search/ubuntu/1/": "search.html",
+		"/search/ubuntu/2/": "search-last.html",
+		"/torrent/5123456/Ubuntu-22-04-3-Desktop-amd64/":            "torrent.html",
+		"/torrent/5123457/Ubuntu-The-Movie-2023-1080p-WEBRip-x264/": "torrent-minimal.html",
+		"/torrent/5000001/Ubuntu-10-04-Desktop-i386/":               "torrent-minimal.html",
+	}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/torrent/5123458/Ubuntu-Wallpapers/" {
+			// a detail page without magnet link
+			_, _ = w.Write([]byte("<html></html>"))
+			return
+		}
+		name, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(readPage(t, name)))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newTestClient(t *testing.T, config Config) interfaces.Client {
+	web, err := httpclient.New(httpclient.Config{Retries: -1, RateLimit: -1, BreakerFailures: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(config, web)
+}
+
+func TestSearchPage(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	mirror := newTestMirror(t)
+	client := newTestClient(t, Config{Mirrors: []string{down.URL, mirror.URL + "/"}, Pages: 2})
+
+	torrents, next := client.SearchPage("ubuntu", "")
+	if next != "" {
+		t.Errorf("next page = %q, want none after the last one", next)
+	}
+
+	var b strings.Builder
+	for _, torrent := range torrents {
+		uploaded := ""
+		if !torrent.UploadedTime().IsZero() {
+			uploaded = torrent.UploadedTime().Format("2006-01-02")
+		}
+		fmt.Fprintf(&b, "%s | %s | %s | %d | %s | %s (%s) | %d/%d | %s\n",
+			torrent.ID, torrent.Title, torrent.InfoHash, torrent.Size, uploaded,
+			torrent.Uploader, torrent.UploaderStatus, torrent.Seeders, torrent.Leechers, torrent.Category)
+	}
+	checkGolden(t, "torrents", b.String())
+
+	// the mirror that answered is tried first
+	if first := client.(x1337).mirrors.list()[0]; first != mirror.URL {
+		t.Errorf("first mirror = %s, want %s", first, mirror.URL)
+	}
+}
+
+func TestSearchPageNext(t *testing.T) {
+	client := newTestClient(t, Config{Mirrors: []string{newTestMirror(t).URL}})
+
+	torrents, next := client.SearchPage("ubuntu", "")
+	if next != "2" {
+		t.Errorf("next page = %q, want 2", next)
+	}
+	if len(torrents) != 2 {
+		t.Errorf("found %d torrents, want the 2 whose detail page has a magnet link", len(torrents))
+	}
+}
+
+func TestFetchDescriptionAndFiles(t *testing.T) {
+	client := newTestClient(t, Config{Mirrors: []string{newTestMirror(t).URL}})
+	torrent := interfaces.Torrent{ID: "5123456/Ubuntu-22-04-3-Desktop-amd64/"}
+
+	if description := client.FetchTorrentDescription(torrent); !strings.HasPrefix(description, "Description\nUbuntu 22.04.3 LTS") {
+		t.Errorf("description = %q", description)
+	}
+	if files := client.FetchTorrentFiles(torrent); len(files) != 3 {
+		t.Errorf("files = %+v, want 3", files)
+	}
+}
+
+func TestAllMirrorsDown(t *testing.T) {
+	client := newTestClient(t, Config{Mirrors: []string{"http://127.0.0.1:1"}})
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), errAllMirrorsDown.Error()) {
+			t.Errorf("recovered %v, want %v", r, errAllMirrorsDown)
+		}
+	}()
+	client.SearchPage("ubuntu", "")
+}
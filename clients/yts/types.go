@@ -0,0 +1,52 @@
+package yts
+
+import "github.com/ismaelpadilla/gotorrent/clients/httpclient"
+
+type yts struct {
+	web *httpclient.Client
+	// api is the address of the API, apiURL outside of tests
+	api string
+}
+
+type ytsResponse struct {
+	Status        string  `json:"status"`
+	StatusMessage string  `json:"status_message"`
+	Data          ytsData `json:"data"`
+}
+
+type ytsData struct {
+	MovieCount int        `json:"movie_count"`
+	Movies     []ytsMovie `json:"movies"`
+	Movie      ytsMovie   `json:"movie"`
+}
+
+type ytsMovie struct {
+	ID              int          `json:"id"`
+	URL             string       `json:"url"`
+	IMDbCode        string       `json:"imdb_code"`
+	Title           string       `json:"title"`
+	TitleLong       string       `json:"title_long"`
+	Year            int          `json:"year"`
+	Rating          float64      `json:"rating"`
+	Runtime         int          `json:"runtime"`
+	Genres          []string     `json:"genres"`
+	Summary         string       `json:"summary"`
+	DescriptionFull string       `json:"description_full"`
+	Synopsis        string       `json:"synopsis"`
+	Language        string       `json:"language"`
+	Torrents        []ytsTorrent `json:"torrents"`
+}
+
+// ytsTorrent is one of the versions of a movie
+type ytsTorrent struct {
+	URL              string `json:"url"`
+	Hash             string `json:"hash"`
+	Quality          string `json:"quality"`
+	Type             string `json:"type"`
+	VideoCodec       string `json:"video_codec"`
+	AudioChannels    string `json:"audio_channels"`
+	Seeds            int    `json:"seeds"`
+	Peers            int    `json:"peers"`
+	SizeBytes        int    `json:"size_bytes"`
+	DateUploadedUnix int64  `json:"date_uploaded_unix"`
+}
@@ -0,0 +1,228 @@
+package yts
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+	"github.com/skratchdot/open-golang/open"
+)
+
+const (
+	apiURL = "https://yts.mx/api/v2"
+	// number of movies per page
+	pageLimit = 50
+)
+
+// trackers recommended by YTS, added to the magnet links
+var trackers = []string{
+	"udp://open.demonii.com:1337/announce",
+	"udp://tracker.openbittorrent.com:80",
+	"udp://tracker.coppersurfer.tk:6969",
+	"udp://glotorrents.pw:6969/announce",
+	"udp://tracker.opentrackr.org:1337/announce",
+	"udp://torrent.gresille.org:80/announce",
+	"udp://p4p.arenabg.com:1337",
+	"udp://tracker.leechers-paradise.org:6969",
+}
+
+func New(web *httpclient.Client) interfaces.Client {
+	return yts{web: web, api: apiURL}
+}
+
+func (y yts) Name() string {
+	return "yts"
+}
+
+// Search looks for movies by title, or by IMDb id if query is one such as
+// tt1375666. Every version of a movie is a different torrent.
+func (y yts) Search(query string) []interfaces.Torrent {
+	torrents, _ := y.SearchPage(query, "")
+	return torrents
+}
+
+// Latest returns the movies added last, YTS's default order
+func (y yts) Latest() []interfaces.Torrent {
+	return y.Search("")
+}
+
+// SearchPage returns a page of movies, page being the page number
+func (y yts) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	number, err := strconv.Atoi(page)
+	if err != nil || number < 1 {
+		number = 1
+	}
+	response := y.get(y.api + "/list_movies.json?limit=" + strconv.Itoa(pageLimit) +
+		"&page=" + strconv.Itoa(number) + "&query_term=" + url.QueryEscape(query))
+
+	var torrents []interfaces.Torrent
+	for _, movie := range response.Data.Movies {
+		for _, t := range movie.Torrents {
+			torrent := convert(movie, t)
+			torrent.Client = y
+			torrents = append(torrents, torrent)
+		}
+	}
+
+	next := ""
+	if number*pageLimit < response.Data.MovieCount {
+		next = strconv.Itoa(number + 1)
+	}
+	return torrents, next
+}
+
+func convert(movie ytsMovie, t ytsTorrent) interfaces.Torrent {
+	title := fmt.Sprintf("%s [%s] [%s]", movie.TitleLong, t.Quality, sourceName(t.Type))
+	if t.VideoCodec != "" && t.VideoCodec != "x264" {
+		title += " [" + t.VideoCodec + "]"
+	}
+	title += " [YTS]"
+
+	info := release.Parse(title)
+	info.Resolution = t.Quality
+	info.Source = sourceName(t.Type)
+	info.Year = movie.Year
+	info.Group = "YTS"
+	if t.VideoCodec != "" {
+		info.Codec = t.VideoCodec
+	}
+
+	torrent := interfaces.Torrent{
+		ID:          torrentID(movie.ID, t.Hash),
+		Title:       title,
+		Description: description(movie),
+		InfoHash:    strings.ToUpper(t.Hash),
+		MagnetLink:  magnetLink(t.Hash, title),
+		Size:        t.SizeBytes,
+		Uploader:    "YTS",
+		Seeders:     t.Seeds,
+		Leechers:    t.Peers,
+		Category:    category(t.Quality),
+		Release:     info,
+		IMDbID:      movie.IMDbCode,
+		Runtime:     movie.Runtime,
+		Genres:      movie.Genres,
+	}
+	if t.DateUploadedUnix > 0 {
+		torrent.Uploaded = strconv.FormatInt(t.DateUploadedUnix, 10)
+	}
+	return torrent
+}
+
+// torrentID identifies a version of a movie by the movie's id and the
+// version's hash, such as "1234-6A8C...". Every version shares the movie's
+// page and description.
+func torrentID(movieID int, hash string) string {
+	return strconv.Itoa(movieID) + "-" + strings.ToUpper(hash)
+}
+
+// movieID returns the id of the movie of a torrent
+func movieID(torrent interfaces.Torrent) string {
+	id, _, _ := strings.Cut(torrent.ID, "-")
+	return id
+}
+
+// sourceName converts YTS's torrent types, "bluray" and "web"
+func sourceName(torrentType string) string {
+	switch torrentType {
+	case "bluray":
+		return "BluRay"
+	case "web":
+		return "WEB"
+	default:
+		return torrentType
+	}
+}
+
+func category(quality string) interfaces.Category {
+	switch quality {
+	case "2160p":
+		return interfaces.CategoryMovies4K
+	case "3D":
+		return interfaces.CategoryMovies3D
+	case "480p":
+		return interfaces.CategoryMoviesSD
+	default:
+		return interfaces.CategoryMoviesHD
+	}
+}
+
+func magnetLink(hash string, name string) string {
+	link := "magnet:?xt=urn:btih:" + hash + "&dn=" + url.QueryEscape(name)
+	for _, tracker := range trackers {
+		link += "&tr=" + url.QueryEscape(tracker)
+	}
+	return link
+}
+
+// description returns the movie's synopsis, preceded by its rating and
+// language
+func description(movie ytsMovie) string {
+	synopsis := movie.DescriptionFull
+	if synopsis == "" {
+		synopsis = movie.Synopsis
+	}
+	if synopsis == "" {
+		synopsis = movie.Summary
+	}
+	if synopsis == "" {
+		return ""
+	}
+
+	s := ""
+	if movie.Rating > 0 {
+		s += fmt.Sprintf("IMDb rating: %.1f/10\n", movie.Rating)
+	}
+	if movie.Language != "" {
+		s += "Language: " + movie.Language + "\n"
+	}
+	if s != "" {
+		s += "\n"
+	}
+	return s + synopsis
+}
+
+func (y yts) NavigateTo(torrent interfaces.Torrent) {
+	movie := y.get(y.api + "/movie_details.json?movie_id=" + url.QueryEscape(movieID(torrent))).Data.Movie
+	// the address comes from the API, only web pages are opened
+	if !httpclient.WebLink(movie.URL) {
+		log.Panic(fmt.Sprintf("invalid movie page %q", movie.URL))
+	}
+	err := open.Run(movie.URL)
+	if err != nil {
+		log.Panic(err)
+	}
+}
+
+// FetchTorrentDescription returns the synopsis of the torrent's movie
+func (y yts) FetchTorrentDescription(torrent interfaces.Torrent) string {
+	movie := y.get(y.api + "/movie_details.json?movie_id=" + url.QueryEscape(movieID(torrent))).Data.Movie
+	return description(movie)
+}
+
+// FetchTorrentFiles returns no files, YTS doesn't list them
+func (y yts) FetchTorrentFiles(torrent interfaces.Torrent) []interfaces.TorrentFile {
+	return []interfaces.TorrentFile{}
+}
+
+func (y yts) get(url string) ytsResponse {
+	body, err := y.web.Get(url)
+	if err != nil {
+		log.Panic(err)
+	}
+	var response ytsResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		log.Panic(err)
+	}
+	if response.Status != "ok" {
+		log.Panic(response.StatusMessage)
+	}
+	return response
+}
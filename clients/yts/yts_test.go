@@ -0,0 +1,207 @@
+package yts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
+
+func readResponse(t *testing.T, name string) ytsResponse {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response ytsResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+// newTestClient returns a client of an API that answers with the files in
+// testdata, named after the requested endpoint. Requests are sent to
+// requests if it isn't nil.
+func newTestClient(t *testing.T, requests chan<- *http.Request) yts {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			requests <- r
+		}
+		http.ServeFile(w, r, filepath.Join("testdata", strings.TrimPrefix(r.URL.Path, "/")))
+	}))
+	t.Cleanup(s.Close)
+
+	web, err := httpclient.New(httpclient.Config{Retries: -1, RateLimit: -1, BreakerFailures: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return yts{web: web, api: s.URL}
+}
+
+func TestConvert(t *testing.T) {
+	movies := readResponse(t, "list_movies.json").Data.Movies
+	got := convert(movies[0], movies[0].Torrents[0])
+
+	want := interfaces.Torrent{
+		ID:       "3175-58C9C5A2B3F4D0E1A2B3C4D5E6F708192A3B4C5D",
+		Title:    "Inception (2010) [720p] [BluRay] [YTS]",
+		InfoHash: "58C9C5A2B3F4D0E1A2B3C4D5E6F708192A3B4C5D",
+		Size:     1073741824,
+		Uploader: "YTS",
+		Seeders:  250,
+		Leechers: 31,
+		Uploaded: "1446320844",
+		Category: interfaces.CategoryMoviesHD,
+		IMDbID:   "tt1375666",
+		Runtime:  148,
+	}
+	if len(got.Genres) != 4 || got.Genres[0] != "Action" {
+		t.Errorf("genres = %q, want the movie's", got.Genres)
+	}
+	if !strings.HasPrefix(got.MagnetLink, "magnet:?xt=urn:btih:58c9c5a2b3f4d0e1a2b3c4d5e6f708192a3b4c5d&dn=Inception+%282010%29") ||
+		!strings.Contains(got.MagnetLink, "&tr=udp%3A%2F%2Ftracker.opentrackr.org%3A1337%2Fannounce") {
+		t.Errorf("magnet link = %q", got.MagnetLink)
+	}
+	r := got.Release
+	if r.Resolution != "720p" || r.Source != "BluRay" || r.Codec != "x264" || r.Year != 2010 || r.Group != "YTS" {
+		t.Errorf("release = %+v", r)
+	}
+	wantDescription := "IMDb rating: 8.8/10\nLanguage: en\n\nDom Cobb is a skilled thief, the absolute best in the dangerous art of extraction."
+	if got.Description != wantDescription {
+		t.Errorf("description = %q, want %q", got.Description, wantDescription)
+	}
+
+	// the fields checked above are left out of the comparison
+	got.Genres, got.MagnetLink, got.Description = nil, "", ""
+	got.Release = release.Info{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("convert() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConvertVersions(t *testing.T) {
+	movie := readResponse(t, "list_movies.json").Data.Movies[0]
+	uhd := convert(movie, movie.Torrents[1])
+	if uhd.Title != "Inception (2010) [2160p] [WEB] [x265] [YTS]" || uhd.Release.Codec != "x265" || uhd.Uploaded != "" {
+		t.Errorf("convert() = %+v", uhd)
+	}
+
+	// each version is a different torrent of the same movie
+	hd := convert(movie, movie.Torrents[0])
+	if hd.ID == uhd.ID || hd.Key() == uhd.Key() {
+		t.Errorf("versions share id %q and key %q", hd.ID, hd.Key())
+	}
+	if movieID(hd) != "3175" || movieID(uhd) != "3175" {
+		t.Errorf("movie ids = %q and %q, want 3175", movieID(hd), movieID(uhd))
+	}
+}
+
+func TestCategory(t *testing.T) {
+	tests := map[string]interfaces.Category{
+		"480p":  interfaces.CategoryMoviesSD,
+		"720p":  interfaces.CategoryMoviesHD,
+		"1080p": interfaces.CategoryMoviesHD,
+		"2160p": interfaces.CategoryMovies4K,
+		"3D":    interfaces.CategoryMovies3D,
+	}
+	for quality, want := range tests {
+		if got := category(quality); got != want {
+			t.Errorf("category(%q) = %s, want %s", quality, got, want)
+		}
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	y := newTestClient(t, requests)
+
+	tests := []struct {
+		page     string
+		wantPage string
+		wantNext string
+	}{
+		{"", "1", "2"},
+		{"2", "2", "3"},
+		// 120 movies fit in 3 pages of 50
+		{"3", "3", ""},
+		{"nope", "1", "2"},
+	}
+	for _, tt := range tests {
+		torrents, next := y.SearchPage("inception", tt.page)
+		r := <-requests
+		q := r.URL.Query()
+		if r.URL.Path != "/list_movies.json" || q.Get("page") != tt.wantPage || q.Get("limit") != "50" || q.Get("query_term") != "inception" {
+			t.Errorf("SearchPage(%q) requested %s", tt.page, r.URL)
+		}
+		if next != tt.wantNext {
+			t.Errorf("SearchPage(%q) next page = %q, want %q", tt.page, next, tt.wantNext)
+		}
+		if len(torrents) != 3 {
+			t.Fatalf("SearchPage(%q) = %d torrents, want every version of every movie", tt.page, len(torrents))
+		}
+		for _, torrent := range torrents {
+			if torrent.Client == nil || torrent.Client.Name() != "yts" {
+				t.Errorf("torrent %q has no client", torrent.Title)
+			}
+		}
+	}
+}
+
+func TestFetchTorrentDescription(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	y := newTestClient(t, requests)
+
+	got := y.FetchTorrentDescription(interfaces.Torrent{ID: "3175-58C9C5A2B3F4D0E1A2B3C4D5E6F708192A3B4C5D"})
+	if r := <-requests; r.URL.Path != "/movie_details.json" || r.URL.Query().Get("movie_id") != "3175" {
+		t.Errorf("requested %s, want the details of movie 3175", r.URL)
+	}
+	if want := "IMDb rating: 8.8/10\nLanguage: en\n\nDom Cobb is a skilled thief."; got != want {
+		t.Errorf("FetchTorrentDescription() = %q, want %q", got, want)
+	}
+}
+
+func TestNavigateToChecksTheURL(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok", "data": {"movie": {"id": 1, "url": "file:///etc/passwd"}}}`))
+	}))
+	defer s.Close()
+	web, err := httpclient.New(httpclient.Config{Retries: -1, RateLimit: -1, BreakerFailures: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NavigateTo() opened a file: URL")
+		}
+	}()
+	yts{web: web, api: s.URL}.NavigateTo(interfaces.Torrent{ID: "1-ABCD"})
+}
+
+func TestFailedRequest(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "error", "status_message": "Something went wrong"}`))
+	}))
+	defer s.Close()
+	web, err := httpclient.New(httpclient.Config{Retries: -1, RateLimit: -1, BreakerFailures: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "Something went wrong") {
+			t.Errorf("recovered %v, want the status message", r)
+		}
+	}()
+	yts{web: web, api: s.URL}.Search("inception")
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ismaelpadilla/gotorrent/bookmarks"
+	"github.com/spf13/cobra"
+)
+
+var bookmarksCmd = &cobra.Command{
+	Use:   "bookmarks",
+	Short: "Manage bookmarked torrents",
+}
+
+var bookmarksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bookmarked torrents",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		list, err := bookmarks.New(bookmarks.DefaultPath()).List()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for _, b := range list {
+			fmt.Printf("%s %-6s %s  %s\n", b.Added.Format("2006-01-02"), b.Provider, b.InfoHash, b.Title)
+			if b.Note != "" {
+				fmt.Printf("    %s\n", b.Note)
+			}
+		}
+	},
+}
+
+var bookmarksExportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export bookmarks as JSON to a file, or to stdout if no file is given",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		var w io.Writer = os.Stdout
+		if len(args) == 1 {
+			file, err := os.Create(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if err := bookmarks.New(bookmarks.DefaultPath()).Export(w); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var bookmarksImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import bookmarks exported with the export command, from a file or from stdin if no file is given",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		var r io.Reader = os.Stdin
+		if len(args) == 1 {
+			file, err := os.Open(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			r = file
+		}
+
+		n, err := bookmarks.New(bookmarks.DefaultPath()).Import(r)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %d bookmarks\n", n)
+	},
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/feed"
+	"github.com/ismaelpadilla/gotorrent/filter"
+	"github.com/spf13/cobra"
+)
+
+var FeedFilter string
+var FeedBaseURL string
+var FeedOutput string
+
+var feedCmd = &cobra.Command{
+	Use:   "feed <query>",
+	Short: "Print search results as an RSS feed",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		query := strings.Join(args, " ")
+
+		f, err := filter.Parse(FeedFilter)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		providers, err := configuredProviders()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		torrents, errs := clients.Search(providers, query)
+		for _, err := range errs {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		torrents = f.Apply(torrents)
+
+		options := feed.Options{
+			Title:       feed.Title(query),
+			Link:        "https://github.com/ismaelpadilla/gotorrent",
+			Description: feed.Description(query, len(torrents)),
+		}
+		// point enclosures to the .torrent cache of a gotorrent server
+		if FeedBaseURL != "" {
+			base := strings.TrimSuffix(FeedBaseURL, "/")
+			options.TorrentURL = func(infoHash string) string {
+				return base + "/torrents/" + infoHash + ".torrent"
+			}
+		}
+
+		var w io.Writer = os.Stdout
+		if FeedOutput != "" {
+			file, err := os.Create(FeedOutput)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if err := feed.Write(w, options, torrents); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func setFeedFlags() {
+	feedCmd.Flags().StringVar(&FeedFilter, "filter", "", "filter applied to the results, e.g. \"res:1080p seeders:>=20\"")
+	feedCmd.Flags().StringVar(&FeedBaseURL, "base-url", "", "address of a gotorrent server whose .torrent cache is used for enclosures")
+	feedCmd.Flags().StringVarP(&FeedOutput, "output", "o", "", "file to write the feed to, stdout by default")
+}
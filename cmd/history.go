@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ismaelpadilla/gotorrent/history"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List previous searches",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		entries, err := history.New(history.DefaultPath()).Entries()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for _, e := range entries {
+			fmt.Printf("%s %-6s %5d results  %s\n", e.Time.Format("2006-01-02 15:04"), e.Provider, e.Results, e.Query)
+		}
+	},
+}
+
+var historyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete the search history",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		if err := history.New(history.DefaultPath()).Clear(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Println("Search history cleared")
+	},
+}
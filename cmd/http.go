@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/inhies/go-bytesize"
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/spf13/viper"
+)
+
+// httpConfig returns the HTTP options in a config section, such as [http]
+// or [http.providers.tpb]
+func httpConfig(section string) (httpclient.Config, error) {
+	config := httpclient.Config{
+		Timeout:   viper.GetDuration(section + ".timeout"),
+		UserAgent: viper.GetString(section + ".user-agent"),
+		Proxy:     viper.GetString(section + ".proxy"),
+		CABundle:  expandHome(viper.GetString(section + ".ca-bundle")),
+
+		Retries:         viper.GetInt(section + ".retries"),
+		RetryBackoff:    viper.GetDuration(section + ".retry-backoff"),
+		MaxRetryDelay:   viper.GetDuration(section + ".max-retry-delay"),
+		RateLimit:       viper.GetFloat64(section + ".rate-limit"),
+		RateBurst:       viper.GetInt(section + ".rate-burst"),
+		BreakerFailures: viper.GetInt(section + ".breaker-failures"),
+		BreakerCooldown: viper.GetDuration(section + ".breaker-cooldown"),
+	}
+	if size := viper.GetString(section + ".max-response-size"); size != "" {
+		b, err := bytesize.Parse(size)
+		if err != nil || b <= 0 {
+			return httpclient.Config{}, fmt.Errorf("invalid %s.max-response-size %q", section, size)
+		}
+		config.MaxResponseSize = int64(b)
+	}
+	return config, nil
+}
+
+// providerHTTPConfig returns the HTTP options of the providers that have
+// their own, by name
+func providerHTTPConfig() (map[string]httpclient.Config, error) {
+	configs := map[string]httpclient.Config{}
+	for name := range viper.GetStringMap("http.providers") {
+		config, err := httpConfig("http.providers." + name)
+		if err != nil {
+			return nil, err
+		}
+		configs[name] = config
+	}
+	return configs, nil
+}
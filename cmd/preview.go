@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ismaelpadilla/gotorrent/ui"
+	"github.com/ismaelpadilla/gotorrent/xdg"
+	"github.com/spf13/viper"
+)
+
+// previewState is the state of the preview pane, saved between runs
+type previewState struct {
+	Show    bool   `json:"show"`
+	Content string `json:"content"`
+}
+
+// previewStatePath returns the location of the saved state of the preview
+// pane
+func previewStatePath() string {
+	return filepath.Join(xdg.StateDir(), "preview.json")
+}
+
+// previewConfig returns the state of the preview pane saved in the last run.
+// Keys set in the [preview] config section take precedence over it, so the
+// state is only remembered between runs for the keys that aren't set.
+func previewConfig() ui.Preview {
+	state := previewState{Content: "description"}
+	// a missing or unreadable state leaves the defaults
+	if data, err := os.ReadFile(previewStatePath()); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	if viper.IsSet("preview.show") {
+		state.Show = viper.GetBool("preview.show")
+	}
+	if viper.IsSet("preview.content") {
+		state.Content = viper.GetString("preview.content")
+	}
+	return ui.Preview{
+		Show:  state.Show,
+		Files: state.Content == "files",
+	}
+}
+
+// savePreview writes the state of the preview pane to its state file, the
+// config file is left alone
+func savePreview(preview ui.Preview) error {
+	state := previewState{Show: preview.Show, Content: "description"}
+	if preview.Files {
+		state.Content = "files"
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(previewStatePath(), data)
+}
@@ -1,13 +1,22 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/ismaelpadilla/gotorrent/clients/thepiratebay"
+	"github.com/ismaelpadilla/gotorrent/bookmarks"
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/clients/local"
+	"github.com/ismaelpadilla/gotorrent/clients/rss"
+	"github.com/ismaelpadilla/gotorrent/clients/x1337"
+	"github.com/ismaelpadilla/gotorrent/history"
+	"github.com/ismaelpadilla/gotorrent/risk"
 	"github.com/ismaelpadilla/gotorrent/ui"
+	"github.com/ismaelpadilla/gotorrent/ui/keys"
+	"github.com/ismaelpadilla/gotorrent/ui/theme"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -15,16 +24,57 @@ import (
 var Debug bool
 var Persist bool
 var DownloadFolder string
+var Private bool
+var Provider string
+var IMDbID string
+var Season int
+var Episode int
 
 var rootCmd = &cobra.Command{
 	Use:   "gotorrent <query>",
-	Short: "gotorrent is a TUI for searching torrents in ThePirateBay",
-	Run: func(_ *cobra.Command, args []string) {
+	Short: "gotorrent is a TUI for searching torrents in ThePirateBay, Nyaa, 1337x, YTS and EZTV",
+	Args:  cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
 		DownloadFolder = viper.GetString("download-folder")
+		Private = viper.GetBool("private")
+		Provider = viper.GetString("provider")
+
+		if err := checkEpisodeFlags(IMDbID, Season, Episode); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 
 		query := strings.Join(args, " ")
+		if IMDbID != "" {
+			query = episodeQuery(IMDbID, Season, Episode, query)
+			// EZTV is the only provider that searches episodes by IMDb id
+			if !cmd.Flags().Changed("provider") {
+				Provider = "eztv"
+			}
+		}
 
-		client := thepiratebay.New()
+		client, err := clients.Get(Provider)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := configureKeys(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		columns := viper.GetStringSlice("columns")
+		if err := ui.CheckColumns(columns); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		t, err := loadTheme()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 
 		// DownloadLocation represents a folder, it should end with "/"
 		if DownloadFolder != "" && !strings.HasSuffix(DownloadFolder, "/") {
@@ -36,11 +86,25 @@ var rootCmd = &cobra.Command{
 			Persist:        Persist,
 			DownloadFolder: DownloadFolder,
 			Debug:          Debug,
+			Columns:        columns,
+			Risk: risk.Config{
+				UntrustedUploaders: viper.GetStringSlice("risk.untrusted-uploaders"),
+				Keywords:           viper.GetStringSlice("risk.keywords"),
+			},
+			Theme:       t,
+			Preview:     previewConfig(),
+			SavePreview: savePreview,
 		}
+		if !Private {
+			config.History = history.New(history.DefaultPath())
+		}
+		config.Bookmarks = bookmarks.New(bookmarks.DefaultPath())
 
-		p := tea.NewProgram(ui.InitialModel(query, config),
-			tea.WithAltScreen(),
-		)
+		options := []tea.ProgramOption{tea.WithAltScreen()}
+		if viper.GetBool("mouse") {
+			options = append(options, tea.WithMouseCellMotion())
+		}
+		p := tea.NewProgram(ui.InitialModel(query, config), options...)
 
 		if err := p.Start(); err != nil {
 			fmt.Printf("An error ocurred: %v", err)
@@ -49,8 +113,59 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// configureKeys remaps keys with the [keys] config section
+func configureKeys() error {
+	remap := viper.GetStringMapStringSlice("keys")
+	delete(remap, "preset")
+	return keys.Configure(viper.GetString("keys.preset"), remap)
+}
+
+// loadTheme returns the theme in the [theme] config section, or no colors at
+// all if the NO_COLOR environment variable is set
+func loadTheme() (theme.Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return theme.Plain(), nil
+	}
+	var colors theme.Colors
+	if err := viper.UnmarshalKey("theme.colors", &colors); err != nil {
+		return theme.Theme{}, err
+	}
+	return theme.Get(viper.GetString("theme.name"), colors)
+}
+
+// checkEpisodeFlags returns an error if --season or --episode are used
+// without the flags they narrow down
+func checkEpisodeFlags(imdbID string, season int, episode int) error {
+	switch {
+	case season < 0 || episode < 0:
+		return errors.New("--season and --episode must be positive")
+	case imdbID == "" && (season > 0 || episode > 0):
+		return errors.New("--season and --episode can only be used with --imdb")
+	case episode > 0 && season == 0:
+		return errors.New("--episode can only be used with --season")
+	}
+	return nil
+}
+
+// episodeQuery builds a query such as "tt0903747 S02E05 1080p", which EZTV
+// turns into a search by IMDb id
+func episodeQuery(imdbID string, season int, episode int, query string) string {
+	if !strings.HasPrefix(imdbID, "tt") {
+		imdbID = "tt" + imdbID
+	}
+	s := imdbID
+	switch {
+	case season > 0 && episode > 0:
+		s += fmt.Sprintf(" S%02dE%02d", season, episode)
+	case season > 0:
+		s += fmt.Sprintf(" S%02d", season)
+	}
+	return strings.TrimSpace(s + " " + query)
+}
+
 func Execute() {
 	setFlags()
+	addCommands()
 	loadConfig()
 
 	if err := rootCmd.Execute(); err != nil {
@@ -63,6 +178,25 @@ func setFlags() {
 	rootCmd.Flags().BoolVarP(&Debug, "debug", "d", false, "show debug information")
 	rootCmd.Flags().BoolVarP(&Persist, "persist", "p", false, "keep gotorrent open after selecting torrent")
 	rootCmd.Flags().StringVarP(&DownloadFolder, "download-folder", "f", "", "folder where files are downloaded")
+	rootCmd.Flags().BoolVar(&Private, "private", false, "don't record searches in the history")
+	rootCmd.Flags().StringVar(&IMDbID, "imdb", "", "search a show's episodes by IMDb id, such as tt0903747")
+	rootCmd.Flags().IntVar(&Season, "season", 0, "season to search, used with --imdb")
+	rootCmd.Flags().IntVar(&Episode, "episode", 0, "episode to search, used with --imdb and --season")
+	rootCmd.Flags().StringVarP(&Provider, "provider", "P", "tpb", "provider to search in ("+strings.Join(clients.Names(), ", ")+")")
+	setWatchFlags()
+	setServeFlags()
+	setFeedFlags()
+}
+
+func addCommands() {
+	historyCmd.AddCommand(historyClearCmd)
+	rootCmd.AddCommand(historyCmd)
+	bookmarksCmd.AddCommand(bookmarksListCmd, bookmarksExportCmd, bookmarksImportCmd)
+	rootCmd.AddCommand(bookmarksCmd)
+	watchCmd.AddCommand(watchAddCmd, watchListCmd, watchRemoveCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(feedCmd)
 }
 
 func loadConfig() {
@@ -70,6 +204,29 @@ func loadConfig() {
 	if err != nil {
 		panic(err)
 	}
+	err = viper.BindPFlag("private", rootCmd.Flags().Lookup("private"))
+	if err != nil {
+		panic(err)
+	}
+	err = viper.BindPFlag("provider", rootCmd.Flags().Lookup("provider"))
+	if err != nil {
+		panic(err)
+	}
+	err = viper.BindPFlag("watch.interval", watchCmd.Flags().Lookup("interval"))
+	if err != nil {
+		panic(err)
+	}
+
+	err = viper.BindPFlag("server.http", serveCmd.Flags().Lookup("http"))
+	if err != nil {
+		panic(err)
+	}
+	err = viper.BindPFlag("server.api-key", serveCmd.Flags().Lookup("api-key"))
+	if err != nil {
+		panic(err)
+	}
+	viper.SetDefault("providers", []string{"tpb"})
+	viper.SetDefault("mouse", true)
 
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("$HOME/.config/gotorrent/")
@@ -84,4 +241,45 @@ func loadConfig() {
 			panic(err)
 		}
 	}
+
+	var feeds []rss.Feed
+	err = viper.UnmarshalKey("rss.feeds", &feeds)
+	if err != nil {
+		panic(err)
+	}
+	httpOptions, err := httpConfig("http")
+	if err != nil {
+		panic(err)
+	}
+	providerHTTPOptions, err := providerHTTPConfig()
+	if err != nil {
+		panic(err)
+	}
+	err = clients.Configure(clients.Config{
+		X1337: x1337.Config{
+			Mirrors: viper.GetStringSlice("1337x.mirrors"),
+			Pages:   viper.GetInt("1337x.pages"),
+		},
+		RSS: feeds,
+		Local: local.Config{
+			Dir: expandHome(viper.GetString("local.dir")),
+		},
+		HTTP:         httpOptions,
+		ProviderHTTP: providerHTTPOptions,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + strings.TrimPrefix(path, "~")
 }
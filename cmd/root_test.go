@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/viper"
+)
+
+func TestLoadTheme(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("theme.name", nil)
+		viper.Set("theme.colors", nil)
+	})
+	viper.Set("theme.name", "light")
+	viper.Set("theme.colors", map[string]string{"header": "#ff8800"})
+
+	t.Setenv("NO_COLOR", "")
+	got, err := loadTheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Header.GetForeground() != lipgloss.Color("#ff8800") {
+		t.Errorf("header = %v, want the configured color", got.Header.GetForeground())
+	}
+
+	viper.Set("theme.name", "nope")
+	if _, err := loadTheme(); err == nil {
+		t.Error("loadTheme() accepted an unknown theme")
+	}
+
+	// NO_COLOR wins over the configured theme, whatever it is
+	t.Setenv("NO_COLOR", "1")
+	got, err = loadTheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Header.GetForeground().(lipgloss.NoColor); !ok || !got.Selected.GetReverse() {
+		t.Errorf("loadTheme() with NO_COLOR = %+v, want the plain theme", got)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/downloader"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/server"
+	"github.com/ismaelpadilla/gotorrent/torrentcache"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var ServeAddress string
+var ServeAPIKey string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the configured providers through Torznab and JSON REST APIs",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		providers, err := configuredProviders()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		proxies, err := server.ParseProxies(viper.GetStringSlice("server.trusted-proxies"))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		logger := log.New(os.Stdout, "", log.LstdFlags)
+		handler := server.New(server.Config{
+			Providers:      providers,
+			APIKey:         viper.GetString("server.api-key"),
+			CORSOrigins:    viper.GetStringSlice("server.cors-origins"),
+			TrustedProxies: proxies,
+			Downloader:     downloader.New(viper.GetString("downloader.command")),
+			Cache:          torrentcache.New(torrentcache.DefaultDir(), clients.HTTPClient()),
+			Logger:         logger,
+		})
+
+		address := viper.GetString("server.http")
+		logger.Printf("listening on %s", address)
+		if viper.GetString("server.api-key") == "" && !loopback(address) {
+			logger.Printf("warning: no API key is set, anyone who can reach %s can use the API", address)
+		}
+		s := &http.Server{
+			Addr:              address,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if err := s.ListenAndServe(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+// configuredProviders returns a client for each provider in the providers
+// config key
+func configuredProviders() ([]interfaces.Client, error) {
+	var providers []interfaces.Client
+	for _, name := range viper.GetStringSlice("providers") {
+		client, err := clients.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, client)
+	}
+	return providers, nil
+}
+
+// loopback returns true if an address only accepts connections from this
+// machine
+func loopback(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func setServeFlags() {
+	serveCmd.Flags().StringVar(&ServeAddress, "http", "127.0.0.1:9117", "address to listen on, use :9117 to listen on every interface")
+	serveCmd.Flags().StringVar(&ServeAPIKey, "api-key", "", "API key clients must send, no key is required if empty")
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/downloader"
+	"github.com/ismaelpadilla/gotorrent/watch"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var WatchInterval time.Duration
+var WatchOnce bool
+var WatchNotifyExisting bool
+
+var SavedSearchProvider string
+var SavedSearchFilter string
+var SavedSearchActions []string
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Periodically run saved searches and act on new torrents",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		searches, err := watch.NewSearchStore(watch.DefaultSearchesPath()).List()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if len(searches) == 0 {
+			fmt.Println("There are no saved searches, add one with `gotorrent watch add`")
+			os.Exit(1)
+		}
+
+		state, err := watch.LoadState(watch.DefaultStatePath())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		logger := log.New(os.Stdout, "", log.LstdFlags)
+		w := watch.Watcher{
+			Searches:       searches,
+			Client:         clients.Get,
+			State:          state,
+			Actions:        newWatchActions(logger),
+			Clock:          watch.RealClock(),
+			Logger:         logger,
+			Interval:       viper.GetDuration("watch.interval"),
+			NotifyExisting: WatchNotifyExisting,
+		}
+
+		if WatchOnce {
+			w.RunOnce()
+			return
+		}
+
+		if w.Interval <= 0 {
+			fmt.Printf("Invalid interval %s, it must be positive\n", w.Interval)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		logger.Printf("watching %d saved searches every %s", len(searches), w.Interval)
+		if err := w.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+var watchAddCmd = &cobra.Command{
+	Use:   "add <name> <query>",
+	Short: "Save a search to be run by the watcher",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		search := watch.SavedSearch{
+			Name:     args[0],
+			Query:    strings.Join(args[1:], " "),
+			Provider: SavedSearchProvider,
+			Filter:   SavedSearchFilter,
+			Actions:  SavedSearchActions,
+		}
+		if err := search.Validate(newWatchActions(nil)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if _, err := clients.Get(search.Provider); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if err := watch.NewSearchStore(watch.DefaultSearchesPath()).Add(search); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved search %q\n", search.Name)
+	},
+}
+
+var watchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved searches",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		searches, err := watch.NewSearchStore(watch.DefaultSearchesPath()).List()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for _, s := range searches {
+			fmt.Printf("%s: %q on %s", s.Name, s.Query, s.Provider)
+			if s.Filter != "" {
+				fmt.Printf(", filter %q", s.Filter)
+			}
+			fmt.Printf(", actions: %s\n", strings.Join(s.Actions, ", "))
+		}
+	},
+}
+
+var watchRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if err := watch.NewSearchStore(watch.DefaultSearchesPath()).Remove(args[0]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		// forget what the search has seen, so it starts fresh if re-added
+		state, err := watch.LoadState(watch.DefaultStatePath())
+		if err == nil {
+			state.Forget(args[0])
+			err = state.Save()
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed saved search %q\n", args[0])
+	},
+}
+
+func newWatchActions(logger *log.Logger) watch.Actions {
+	return watch.NewActions(watch.ActionsConfig{
+		Logger:        logger,
+		NotifyCommand: viper.GetString("watch.notify-command"),
+		Downloader:    downloader.New(viper.GetString("downloader.command")),
+		Folder:        viper.GetString("watch.folder"),
+	})
+}
+
+func setWatchFlags() {
+	watchCmd.Flags().DurationVar(&WatchInterval, "interval", 30*time.Minute, "time between runs of the saved searches")
+	watchCmd.Flags().BoolVar(&WatchOnce, "once", false, "run the saved searches once and exit")
+	watchCmd.Flags().BoolVar(&WatchNotifyExisting, "notify-existing", false, "act on results found the first time a saved search is run")
+
+	watchAddCmd.Flags().StringVar(&SavedSearchProvider, "provider", "tpb", "provider to search in")
+	watchAddCmd.Flags().StringVar(&SavedSearchFilter, "filter", "", "filter applied to the results, e.g. \"s:3 res:1080p seeders:>=20\"")
+	watchAddCmd.Flags().StringSliceVar(&SavedSearchActions, "action", []string{"log"}, "actions run for new torrents: log, notify, send or folder")
+}
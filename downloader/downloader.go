@@ -0,0 +1,47 @@
+package downloader
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/skratchdot/open-golang/open"
+)
+
+// Downloader hands magnet links to a torrent client.
+type Downloader struct {
+	// Command is run to add a torrent, with {magnet} replaced by the magnet
+	// link, for example "transmission-remote -a {magnet}". If it is empty the
+	// magnet link is opened with the default application.
+	Command string
+}
+
+func New(command string) Downloader {
+	return Downloader{Command: command}
+}
+
+// Send adds a magnet link to the torrent client.
+func (d Downloader) Send(magnetLink string) error {
+	if d.Command == "" {
+		return open.Run(magnetLink)
+	}
+
+	args := strings.Fields(d.Command)
+	replaced := false
+	for i, arg := range args {
+		if strings.Contains(arg, "{magnet}") {
+			args[i] = strings.ReplaceAll(arg, "{magnet}", magnetLink)
+			replaced = true
+		}
+	}
+	if !replaced {
+		args = append(args, magnetLink)
+	}
+
+	// #nosec G204 -- the command comes from the user's configuration
+	output, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/torrentcache"
+)
+
+// namespace used by torrent clients to read torrent metadata from feeds
+const torrentNamespace = "http://xmlns.ezrss.it/0.1/"
+
+// Options configures a feed.
+type Options struct {
+	Title       string
+	Link        string
+	Description string
+	// TorrentURL returns the address of a torrent's .torrent file, used as
+	// the item's enclosure. If nil, torrentcache.URL is used.
+	TorrentURL func(infoHash string) string
+}
+
+type rss struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Torrent string   `xml:"xmlns:torrent,attr"`
+	Channel channel  `xml:"channel"`
+}
+
+type channel struct {
+	Title         string `xml:"title"`
+	Link          string `xml:"link"`
+	Description   string `xml:"description"`
+	LastBuildDate string `xml:"lastBuildDate"`
+	Items         []item `xml:"item"`
+}
+
+type item struct {
+	Title     string      `xml:"title"`
+	Link      string      `xml:"link"`
+	GUID      guid        `xml:"guid"`
+	PubDate   string      `xml:"pubDate,omitempty"`
+	Category  string      `xml:"category,omitempty"`
+	Enclosure *enclosure  `xml:"enclosure,omitempty"`
+	Torrent   torrentInfo `xml:"torrent:torrent"`
+}
+
+type guid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type enclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type torrentInfo struct {
+	FileName      string `xml:"torrent:fileName"`
+	ContentLength int    `xml:"torrent:contentLength"`
+	InfoHash      string `xml:"torrent:infoHash"`
+	MagnetURI     string `xml:"torrent:magnetURI"`
+	Seeds         int    `xml:"torrent:seeds"`
+	Peers         int    `xml:"torrent:peers"`
+}
+
+// Write renders the torrents as an RSS 2.0 feed. Each item links to the
+// torrent's magnet link, has its .torrent file as enclosure if its info-hash
+// is known, and includes seeders and peers in the torrent namespace. GUIDs
+// are the torrents' keys, their info-hashes or otherwise their provider and
+// ID, so they are stable across runs.
+func Write(w io.Writer, options Options, torrents []interfaces.Torrent) error {
+	torrentURL := options.TorrentURL
+	if torrentURL == nil {
+		torrentURL = torrentcache.URL
+	}
+
+	feed := rss{
+		Version: "2.0",
+		Torrent: torrentNamespace,
+		Channel: channel{
+			Title:         options.Title,
+			Link:          options.Link,
+			Description:   options.Description,
+			LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, t := range torrents {
+		infoHash := strings.ToLower(t.InfoHash)
+		i := item{
+			Title: t.Title,
+			Link:  t.MagnetLink,
+			GUID:  guid{Value: t.Key()},
+			Torrent: torrentInfo{
+				FileName:      t.Title + ".torrent",
+				ContentLength: t.Size,
+				InfoHash:      infoHash,
+				MagnetURI:     t.MagnetLink,
+				Seeds:         t.Seeders,
+				Peers:         t.Seeders + t.Leechers,
+			},
+		}
+		if infoHash != "" {
+			i.Enclosure = &enclosure{
+				URL:    torrentURL(infoHash),
+				Length: t.Size,
+				Type:   "application/x-bittorrent",
+			}
+		}
+		if uploaded := t.UploadedTime(); !uploaded.IsZero() {
+			i.PubDate = uploaded.UTC().Format(time.RFC1123Z)
+		}
+		if t.Category != interfaces.CategoryUnknown {
+			i.Category = t.Category.String()
+		}
+		feed.Channel.Items = append(feed.Channel.Items, i)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Title returns the default title of a feed for a query.
+func Title(query string) string {
+	return "gotorrent: " + query
+}
+
+// Description returns the default description of a feed for a query.
+func Description(query string, results int) string {
+	return "Search results for \"" + query + "\" (" + strconv.Itoa(results) + " torrents)"
+}
@@ -0,0 +1,42 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+func TestWriteGUIDs(t *testing.T) {
+	torrents := []interfaces.Torrent{
+		{Title: "hash", InfoHash: "ABCDEF", MagnetLink: "magnet:?xt=urn:btih:ABCDEF"},
+		{Title: "id", ID: "42", MagnetLink: "magnet:?dn=id"},
+		{Title: "link", MagnetLink: "magnet:?dn=link"},
+	}
+	var b strings.Builder
+	err := Write(&b, Options{TorrentURL: func(infoHash string) string { return "http://cache/" + infoHash }}, torrents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed rss
+	if err := xml.Unmarshal([]byte(b.String()), &feed); err != nil {
+		t.Fatalf("%v\n%s", err, b.String())
+	}
+	items := feed.Channel.Items
+	if len(items) != 3 {
+		t.Fatalf("%d items, want 3", len(items))
+	}
+	for i, want := range []string{"abcdef", "id::42", "link:magnet:?dn=link"} {
+		if items[i].GUID.Value != want {
+			t.Errorf("%s: guid = %q, want %q", items[i].Title, items[i].GUID.Value, want)
+		}
+	}
+	if items[0].Enclosure == nil || items[0].Enclosure.URL != "http://cache/abcdef" {
+		t.Errorf("enclosure = %+v, want the cached .torrent file", items[0].Enclosure)
+	}
+	if items[1].Enclosure != nil {
+		t.Errorf("enclosure of a torrent without info-hash = %+v, want none", items[1].Enclosure)
+	}
+}
@@ -0,0 +1,197 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/inhies/go-bytesize"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// Filter is a parsed filter expression. An expression is a list of terms
+// separated by spaces, all of which must match for a torrent to be kept:
+//
+//	1080p -cam res:>=1080p codec:x265 s:3 seeders:>20 size:<5GB
+//
+// Words without a qualifier must be contained in the title, words prefixed
+// with '-' must not be. Numeric qualifiers support the >, >=, <, <= and =
+// operators.
+type Filter struct {
+	terms []term
+}
+
+type term struct {
+	field  string
+	op     string
+	value  string
+	number float64
+	negate bool
+}
+
+var textFields = map[string]func(interfaces.Torrent) string{
+	"title":  func(t interfaces.Torrent) string { return t.Title },
+	"codec":  func(t interfaces.Torrent) string { return t.Release.Codec },
+	"source": func(t interfaces.Torrent) string { return t.Release.Source },
+	"hdr":    func(t interfaces.Torrent) string { return t.Release.HDR },
+	"audio":  func(t interfaces.Torrent) string { return t.Release.Audio },
+	"group":  func(t interfaces.Torrent) string { return t.Release.Group },
+}
+
+var numberFields = map[string]func(interfaces.Torrent) float64{
+	"res":      func(t interfaces.Torrent) float64 { return float64(t.Release.ResolutionHeight()) },
+	"season":   func(t interfaces.Torrent) float64 { return float64(t.Release.Season) },
+	"episode":  func(t interfaces.Torrent) float64 { return float64(t.Release.Episode) },
+	"year":     func(t interfaces.Torrent) float64 { return float64(t.Release.Year) },
+	"seeders":  func(t interfaces.Torrent) float64 { return float64(t.Seeders) },
+	"leechers": func(t interfaces.Torrent) float64 { return float64(t.Leechers) },
+	"size":     func(t interfaces.Torrent) float64 { return float64(t.Size) },
+}
+
+var aliases = map[string]string{
+	"resolution": "res",
+	"s":          "season",
+	"e":          "episode",
+	"ep":         "episode",
+	"se":         "seeders",
+	"le":         "leechers",
+	"src":        "source",
+}
+
+// Parse parses a filter expression. An empty expression matches everything.
+func Parse(expression string) (Filter, error) {
+	return ParseWords(strings.Fields(expression))
+}
+
+// ParseWords parses the terms of a filter expression that has already been
+// split, so values may contain spaces.
+func ParseWords(words []string) (Filter, error) {
+	var f Filter
+	for _, word := range words {
+		t, err := parseTerm(word)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.terms = append(f.terms, t)
+	}
+	return f, nil
+}
+
+// IsField returns true if name is a field that can be used as a qualifier,
+// such as "seeders" in "seeders:>20".
+func IsField(name string) bool {
+	name = strings.ToLower(name)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	_, text := textFields[name]
+	_, number := numberFields[name]
+	return text || number
+}
+
+func parseTerm(word string) (term, error) {
+	t := term{field: "title", op: "="}
+	if strings.HasPrefix(word, "-") && len(word) > 1 {
+		t.negate = true
+		word = word[1:]
+	}
+
+	field, value, found := strings.Cut(word, ":")
+	if !found {
+		t.value = strings.ToLower(word)
+		return t, nil
+	}
+
+	field = strings.ToLower(field)
+	if alias, ok := aliases[field]; ok {
+		field = alias
+	}
+	t.field = field
+
+	if _, ok := textFields[field]; ok {
+		t.value = strings.ToLower(value)
+		return t, nil
+	}
+	if _, ok := numberFields[field]; !ok {
+		return term{}, fmt.Errorf("unknown filter field %q", field)
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, op) {
+			t.op = op
+			value = value[len(op):]
+			break
+		}
+	}
+	t.value = value
+
+	number, err := parseNumber(field, value)
+	if err != nil {
+		return term{}, fmt.Errorf("invalid value %q for %s: %w", value, field, err)
+	}
+	t.number = number
+	return t, nil
+}
+
+func parseNumber(field, value string) (float64, error) {
+	switch field {
+	case "size":
+		b, err := bytesize.Parse(value)
+		return float64(b), err
+	case "res":
+		value = strings.TrimSuffix(strings.ToLower(value), "p")
+		if value == "4k" {
+			value = "2160"
+		}
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// Empty returns true if the filter has no terms.
+func (f Filter) Empty() bool {
+	return len(f.terms) == 0
+}
+
+// Match returns true if the torrent satisfies every term in the filter.
+func (f Filter) Match(torrent interfaces.Torrent) bool {
+	for _, t := range f.terms {
+		if t.match(torrent) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the torrents that match the filter.
+func (f Filter) Apply(torrents []interfaces.Torrent) []interfaces.Torrent {
+	if f.Empty() {
+		return torrents
+	}
+	var filtered []interfaces.Torrent
+	for _, t := range torrents {
+		if f.Match(t) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+func (t term) match(torrent interfaces.Torrent) bool {
+	if getText, ok := textFields[t.field]; ok {
+		return strings.Contains(strings.ToLower(getText(torrent)), t.value)
+	}
+
+	n := numberFields[t.field](torrent)
+	switch t.op {
+	case ">":
+		return n > t.number
+	case ">=":
+		return n >= t.number
+	case "<":
+		return n < t.number
+	case "<=":
+		return n <= t.number
+	default:
+		return n == t.number
+	}
+}
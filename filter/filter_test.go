@@ -0,0 +1,170 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{
+		"colour:red",
+		"seeders:many",
+		"seeders:>",
+		"size:<lots",
+		"res:high",
+		"s:>=x",
+	} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("Parse(%q) returned no error", expression)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []term
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"1080P", []term{{field: "title", op: "=", value: "1080p"}}},
+		{"-cam", []term{{field: "title", op: "=", value: "cam", negate: true}}},
+		{"-", []term{{field: "title", op: "=", value: "-"}}},
+		{"Codec:X265", []term{{field: "codec", op: "=", value: "x265"}}},
+		{"src:web", []term{{field: "source", op: "=", value: "web"}}},
+		{"seeders:>20", []term{{field: "seeders", op: ">", value: "20", number: 20}}},
+		{"se:>=5", []term{{field: "seeders", op: ">=", value: "5", number: 5}}},
+		{"le:<=3", []term{{field: "leechers", op: "<=", value: "3", number: 3}}},
+		{"s:3", []term{{field: "season", op: "=", value: "3", number: 3}}},
+		{"ep:=12", []term{{field: "episode", op: "=", value: "12", number: 12}}},
+		{"res:>=1080p", []term{{field: "res", op: ">=", value: "1080p", number: 1080}}},
+		{"resolution:4k", []term{{field: "res", op: "=", value: "4k", number: 2160}}},
+		{"size:<1KB", []term{{field: "size", op: "<", value: "1KB", number: 1024}}},
+		{"-year:<2000", []term{{field: "year", op: "<", value: "2000", number: 2000, negate: true}}},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.expression)
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", tt.expression, err)
+			continue
+		}
+		if len(f.terms) != len(tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.expression, f.terms, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if f.terms[i] != tt.want[i] {
+				t.Errorf("Parse(%q) term %d = %+v, want %+v", tt.expression, i, f.terms[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseWords(t *testing.T) {
+	f, err := ParseWords([]string{"group:some group", "seeders:>1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	movie := interfaces.Torrent{Title: "Movie", Seeders: 10, Release: release.Info{Group: "Some Group"}}
+	if !f.Match(movie) {
+		t.Error("a value with a space didn't match")
+	}
+}
+
+func TestIsField(t *testing.T) {
+	for _, name := range []string{"title", "Seeders", "s", "res", "resolution", "size", "group"} {
+		if !IsField(name) {
+			t.Errorf("IsField(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"provider", "cat", "", "colour"} {
+		if IsField(name) {
+			t.Errorf("IsField(%q) = true, want false", name)
+		}
+	}
+}
+
+func torrent(title string, seeders int, size int) interfaces.Torrent {
+	return interfaces.Torrent{
+		Title:   title,
+		Seeders: seeders,
+		Size:    size,
+		Release: release.Parse(title),
+	}
+}
+
+func TestMatch(t *testing.T) {
+	show := torrent("Show.Name.S03E05.1080p.WEB-DL.DDP5.1.H.264-GROUP", 25, 2<<30)
+	movie := torrent("Movie.Title.2019.2160p.UHD.BluRay.REMUX.HDR.HEVC.Atmos-EPSiLON", 5, 60<<30)
+	cam := torrent("Movie.Title.2019.HDCAM.x264-NOGRP", 100, 700<<20)
+
+	tests := []struct {
+		expression string
+		torrent    interfaces.Torrent
+		want       bool
+	}{
+		{"", show, true},
+		{"show name", show, true},
+		{"show.name.s04", show, false},
+		{"show.name", show, true},
+		{"SHOW", show, true},
+		{"-cam", cam, false},
+		{"-cam", movie, true},
+		{"res:>=1080p", show, true},
+		{"res:>=1080p", cam, false},
+		{"res:4k", movie, true},
+		{"res:<2160", movie, false},
+		{"codec:x265", movie, true},
+		{"codec:x265", show, false},
+		{"source:web", show, true},
+		{"hdr:hdr", movie, true},
+		{"audio:atmos", movie, true},
+		{"group:epsilon", movie, true},
+		{"s:3", show, true},
+		{"s:3 e:5", show, true},
+		{"s:3 e:6", show, false},
+		{"season:>3", show, false},
+		{"year:2019", movie, true},
+		{"-year:2019", movie, false},
+		{"seeders:>20", show, true},
+		{"seeders:>20", movie, false},
+		{"seeders:>=25", show, true},
+		{"leechers:0", show, true},
+		{"size:<5GB", show, true},
+		{"size:<5GB", movie, false},
+		{"size:>50GB", movie, true},
+		{"1080p seeders:>20 size:<5GB -cam", show, true},
+		{"1080p seeders:>20 size:<5GB -web", show, false},
+	}
+	for _, tt := range tests {
+		f, err := Parse(tt.expression)
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", tt.expression, err)
+			continue
+		}
+		if got := f.Match(tt.torrent); got != tt.want {
+			t.Errorf("Parse(%q).Match(%q) = %t, want %t", tt.expression, tt.torrent.Title, got, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	torrents := []interfaces.Torrent{
+		torrent("a 1080p", 1, 0),
+		torrent("b 720p", 2, 0),
+		torrent("c 1080p", 3, 0),
+	}
+
+	f, _ := Parse("")
+	if got := f.Apply(torrents); len(got) != 3 {
+		t.Errorf("an empty filter kept %d torrents, want 3", len(got))
+	}
+
+	f, _ = Parse("res:1080 seeders:>1")
+	got := f.Apply(torrents)
+	if len(got) != 1 || got[0].Title != "c 1080p" {
+		t.Errorf("Apply kept %+v, want c", got)
+	}
+}
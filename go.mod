@@ -2,7 +2,11 @@ module github.com/ismaelpadilla/gotorrent
 
 go 1.18
 
-require github.com/charmbracelet/lipgloss v0.5.0
+require (
+	github.com/charmbracelet/lipgloss v0.5.0
+	github.com/muesli/reflow v0.3.0
+	github.com/muesli/termenv v0.12.0
+)
 
 require (
 	github.com/containerd/console v1.0.3 // indirect
@@ -14,8 +18,6 @@ require (
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
 	github.com/muesli/ansi v0.0.0-20211031195517-c9f0611b6c70 // indirect
 	github.com/muesli/cancelreader v0.2.1 // indirect
-	github.com/muesli/reflow v0.3.0 // indirect
-	github.com/muesli/termenv v0.12.0 // indirect
 	github.com/pelletier/go-toml v1.9.5 // indirect
 	github.com/pelletier/go-toml/v2 v2.0.1 // indirect
 	github.com/spf13/afero v1.8.2 // indirect
@@ -35,7 +37,7 @@ require (
 	github.com/charmbracelet/bubbletea v0.22.0
 	github.com/inhies/go-bytesize v0.0.0-20220417184213-4913239db9cf
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
-	github.com/mattn/go-runewidth v0.0.13 // indirect
+	github.com/mattn/go-runewidth v0.0.13
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/skratchdot/open-golang v0.0.0-20200116055534-eef842397966
 	github.com/spf13/cobra v1.5.0
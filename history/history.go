@@ -0,0 +1,113 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/xdg"
+)
+
+// Entry is a search made by the user.
+type Entry struct {
+	Query    string    `json:"query"`
+	Provider string    `json:"provider"`
+	Time     time.Time `json:"time"`
+	Results  int       `json:"results"`
+}
+
+// Store keeps the search history in a file, one JSON encoded entry per line.
+type Store struct {
+	path string
+}
+
+// DefaultPath returns the location of the history file.
+func DefaultPath() string {
+	return filepath.Join(xdg.StateDir(), "history.jsonl")
+}
+
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Add appends an entry to the history.
+func (s *Store) Add(e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Entries returns every entry in the history, oldest first.
+// A missing history file is not an error.
+func (s *Store) Entries() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e Entry
+		// skip corrupted lines instead of losing the whole history
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Queries returns the queries in the history, oldest first. Repeated queries
+// only appear once, in the position of their most recent use.
+func (s *Store) Queries() ([]string, error) {
+	entries, err := s.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var reversed []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		q := entries[i].Query
+		if q == "" || seen[q] {
+			continue
+		}
+		seen[q] = true
+		reversed = append(reversed, q)
+	}
+
+	queries := make([]string, len(reversed))
+	for i, q := range reversed {
+		queries[len(reversed)-1-i] = q
+	}
+	return queries, nil
+}
+
+// Clear removes every entry from the history.
+func (s *Store) Clear() error {
+	err := os.Remove(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
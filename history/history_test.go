@@ -0,0 +1,121 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *Store {
+	return New(filepath.Join(t.TempDir(), "state", "history.jsonl"))
+}
+
+func add(t *testing.T, s *Store, queries ...string) {
+	t.Helper()
+	for _, q := range queries {
+		if err := s.Add(Entry{Query: q}); err != nil {
+			t.Fatalf("Add(%q): %v", q, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	want := []Entry{
+		{Query: "ubuntu", Provider: "tpb", Time: time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC), Results: 30},
+		{Query: "debian", Provider: "1337x", Time: time.Date(2022, 8, 2, 11, 30, 0, 0, time.UTC)},
+	}
+	for _, e := range want {
+		if err := s.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := New(s.path).Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %+v, want %+v", got, want)
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("history file permissions = %o, want 600", perm)
+	}
+}
+
+func TestMissingFile(t *testing.T) {
+	s := newTestStore(t)
+	if entries, err := s.Entries(); err != nil || entries != nil {
+		t.Errorf("Entries() = %v, %v, want nothing", entries, err)
+	}
+	if queries, err := s.Queries(); err != nil || len(queries) != 0 {
+		t.Errorf("Queries() = %v, %v, want nothing", queries, err)
+	}
+	if err := s.Clear(); err != nil {
+		t.Errorf("Clear() = %v", err)
+	}
+}
+
+func TestCorruptedLines(t *testing.T) {
+	s := newTestStore(t)
+	add(t, s, "ubuntu")
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString("{not json\n"); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	add(t, s, "debian")
+
+	queries, err := s.Queries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ubuntu", "debian"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("Queries() = %q, want %q", queries, want)
+	}
+}
+
+func TestQueriesDedupe(t *testing.T) {
+	s := newTestStore(t)
+	add(t, s, "ubuntu", "debian", "", "ubuntu", "arch", "debian")
+
+	queries, err := s.Queries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// repeated queries move to their most recent position
+	if want := []string{"ubuntu", "arch", "debian"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("Queries() = %q, want %q", queries, want)
+	}
+	entries, err := s.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 6 {
+		t.Errorf("%d entries, want all 6 kept", len(entries))
+	}
+}
+
+func TestClear(t *testing.T) {
+	s := newTestStore(t)
+	add(t, s, "ubuntu")
+	if err := s.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if queries, err := s.Queries(); err != nil || len(queries) != 0 {
+		t.Errorf("Queries() after Clear() = %q, %v, want nothing", queries, err)
+	}
+	add(t, s, "debian")
+	if queries, _ := s.Queries(); !reflect.DeepEqual(queries, []string{"debian"}) {
+		t.Errorf("Queries() = %q, want the new search only", queries)
+	}
+}
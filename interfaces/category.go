@@ -0,0 +1,89 @@
+package interfaces
+
+// Category is a torrent category, using the Newznab/Torznab numbering.
+// Top level categories are multiples of 1000, subcategories share the
+// thousands digit with their parent.
+type Category int
+
+const (
+	CategoryUnknown   Category = 0
+	CategoryConsole   Category = 1000
+	CategoryMovies    Category = 2000
+	CategoryMoviesSD  Category = 2030
+	CategoryMoviesHD  Category = 2040
+	CategoryMovies4K  Category = 2045
+	CategoryMovies3D  Category = 2060
+	CategoryAudio     Category = 3000
+	CategoryAudioMP3  Category = 3010
+	CategoryAudioVid  Category = 3020
+	CategoryAudioBook Category = 3030
+	CategoryPC        Category = 4000
+	CategoryPCGames   Category = 4050
+	CategoryTV        Category = 5000
+	CategoryTVSD      Category = 5030
+	CategoryTVHD      Category = 5040
+	CategoryTV4K      Category = 5045
+	CategoryTVAnime   Category = 5070
+	CategoryXXX       Category = 6000
+	CategoryBooks     Category = 7000
+	CategoryEBooks    Category = 7020
+	CategoryComics    Category = 7030
+	CategoryOther     Category = 8000
+)
+
+var categoryNames = map[Category]string{
+	CategoryConsole:   "Console",
+	CategoryMovies:    "Movies",
+	CategoryMoviesSD:  "Movies/SD",
+	CategoryMoviesHD:  "Movies/HD",
+	CategoryMovies4K:  "Movies/UHD",
+	CategoryMovies3D:  "Movies/3D",
+	CategoryAudio:     "Audio",
+	CategoryAudioMP3:  "Audio/MP3",
+	CategoryAudioVid:  "Audio/Video",
+	CategoryAudioBook: "Audio/Audiobook",
+	CategoryPC:        "PC",
+	CategoryPCGames:   "PC/Games",
+	CategoryTV:        "TV",
+	CategoryTVSD:      "TV/SD",
+	CategoryTVHD:      "TV/HD",
+	CategoryTV4K:      "TV/UHD",
+	CategoryTVAnime:   "TV/Anime",
+	CategoryXXX:       "XXX",
+	CategoryBooks:     "Books",
+	CategoryEBooks:    "Books/EBook",
+	CategoryComics:    "Books/Comics",
+	CategoryOther:     "Other",
+}
+
+// Categories returns every known category.
+func Categories() []Category {
+	categories := make([]Category, 0, len(categoryNames))
+	for c := range categoryNames {
+		categories = append(categories, c)
+	}
+	return categories
+}
+
+func (c Category) String() string {
+	if name, ok := categoryNames[c]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// Parent returns the top level category of a subcategory.
+func (c Category) Parent() Category {
+	return c / 1000 * 1000
+}
+
+// IsParent returns true if the category is a top level category.
+func (c Category) IsParent() bool {
+	return c == c.Parent()
+}
+
+// Matches returns true if c is the same category as other, or if other is a
+// top level category and c is one of its subcategories.
+func (c Category) Matches(other Category) bool {
+	return c == other || (other.IsParent() && c.Parent() == other)
+}
@@ -1,7 +1,14 @@
 package interfaces
 
 type Client interface {
+	// Name returns a short name identifying the provider, such as "tpb"
+	Name() string
+	// Search returns the first page of results
 	Search(query string) []Torrent
+	// SearchPage returns a page of results and the token of the next page,
+	// or "" if there are no more. page is "" for the first page, or a token
+	// returned by a previous call.
+	SearchPage(query string, page string) ([]Torrent, string)
 	NavigateTo(torrent Torrent)
 	FetchTorrentDescription(torrent Torrent) string
 	FetchTorrentFiles(torrent Torrent) []TorrentFile
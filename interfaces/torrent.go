@@ -1,25 +1,69 @@
 package interfaces
 
-import "github.com/inhies/go-bytesize"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inhies/go-bytesize"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
 
 type Torrent struct {
-	Client      Client
-	ID          string
-	Title       string
-	Description string
-	InfoHash    string
-	Files       []TorrentFile
-	MagnetLink  string
-	Size        int
-	Uploaded    string
-	Seeders     int
-	Leechers    int
+	Client         Client
+	ID             string
+	Title          string
+	Description    string
+	InfoHash       string
+	Files          []TorrentFile
+	MagnetLink     string
+	Size           int
+	Uploaded       string
+	Uploader       string
+	UploaderStatus string
+	Seeders        int
+	Leechers       int
+	Category       Category
+	Release        release.Info
+	// IMDbID, Runtime (in minutes) and Genres are only known for movies and
+	// TV shows by some providers
+	IMDbID  string
+	Runtime int
+	Genres  []string
 }
 
 func (t Torrent) GetPrettySize() string {
 	return bytesize.New(float64(t.Size)).String()
 }
 
+// UploadedTime returns the time the torrent was uploaded, Uploaded being a unix
+// timestamp. It returns the zero time if Uploaded is not valid.
+func (t Torrent) UploadedTime() time.Time {
+	timestamp, err := strconv.ParseInt(t.Uploaded, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(timestamp, 0)
+}
+
+// Key identifies the torrent across searches: its info-hash, or if it has
+// none, its provider and ID, its magnet link or its title.
+func (t Torrent) Key() string {
+	switch {
+	case t.InfoHash != "":
+		return strings.ToLower(t.InfoHash)
+	case t.ID != "":
+		provider := ""
+		if t.Client != nil {
+			provider = t.Client.Name()
+		}
+		return "id:" + provider + ":" + t.ID
+	case t.MagnetLink != "":
+		return "link:" + t.MagnetLink
+	}
+	return "title:" + t.Title
+}
+
 func (t Torrent) FetchDescription() string {
 	return t.Client.FetchTorrentDescription(t)
 }
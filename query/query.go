@@ -0,0 +1,322 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/filter"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// Query is a parsed search query, such as:
+//
+//	ubuntu provider:tpb,nyaa cat:apps seeders:>20 size:<5GB after:2024-01 -beta "exact phrase"
+//
+// Words and phrases are sent to the providers, phrases must also be
+// contained in the titles of the results and words prefixed with '-' must
+// not be. provider selects the providers to search in, cat the categories
+// of the results and after and before when they were uploaded. Any field of
+// the results filter can be used too.
+type Query struct {
+	// Text is what the providers search for, the words and phrases of the
+	// query without its qualifiers
+	Text string
+	// Phrases must be contained in the titles of the results
+	Phrases []string
+	// Providers are the names of the providers to search in, or none to
+	// search in the current one
+	Providers []string
+	// Categories are the categories the results are in, any of them
+	Categories []interfaces.Category
+	// After and Before limit when the results were uploaded, they're zero
+	// if they're not set
+	After  time.Time
+	Before time.Time
+
+	excluded []string
+	filter   filter.Filter
+}
+
+// Searcher is implemented by clients that can apply some of a query's
+// qualifiers in the provider, such as its categories. The results are still
+// checked against the query afterwards.
+type Searcher interface {
+	// SearchQuery returns a page of results, like SearchPage
+	SearchQuery(q Query, page string) ([]interfaces.Torrent, string)
+}
+
+// categoryNames are the names used for categories other than their own, such
+// as "movies/hd"
+var categoryNames = map[string]interfaces.Category{
+	"apps":       interfaces.CategoryPC,
+	"software":   interfaces.CategoryPC,
+	"games":      interfaces.CategoryPCGames,
+	"music":      interfaces.CategoryAudio,
+	"audiobooks": interfaces.CategoryAudioBook,
+	"video":      interfaces.CategoryMovies,
+	"anime":      interfaces.CategoryTVAnime,
+	"ebooks":     interfaces.CategoryEBooks,
+	"comics":     interfaces.CategoryComics,
+	"porn":       interfaces.CategoryXXX,
+}
+
+// dateLayouts are the formats of after and before, from the most precise
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+// Parse parses a search query. Qualifiers with unknown fields, such as
+// "Re:Zero", are searched for as words.
+func Parse(s string) (Query, error) {
+	var q Query
+	var text, filterWords []string
+	for _, t := range split(s) {
+		word, negate := t.text, false
+		if t.negated {
+			word, negate = word[1:], true
+		}
+		if t.quoted {
+			if negate {
+				q.excluded = append(q.excluded, strings.ToLower(word))
+			} else {
+				q.Phrases = append(q.Phrases, word)
+				text = append(text, word)
+			}
+			continue
+		}
+
+		field, value, found := strings.Cut(word, ":")
+		field = strings.ToLower(field)
+		switch {
+		case found && isQueryField(field):
+			if negate {
+				return Query{}, fmt.Errorf("%s can't be negated", field)
+			}
+			if err := q.setField(field, value); err != nil {
+				return Query{}, err
+			}
+		case found && filter.IsField(field):
+			filterWords = append(filterWords, t.text)
+		case negate:
+			q.excluded = append(q.excluded, strings.ToLower(word))
+		default:
+			text = append(text, t.text)
+		}
+	}
+
+	f, err := filter.ParseWords(filterWords)
+	if err != nil {
+		return Query{}, err
+	}
+	q.filter = f
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+func isQueryField(field string) bool {
+	switch field {
+	case "provider", "providers", "cat", "category", "after", "before":
+		return true
+	}
+	return false
+}
+
+func (q *Query) setField(field string, value string) error {
+	if value == "" {
+		return fmt.Errorf("no value for %s", field)
+	}
+	switch field {
+	case "provider", "providers":
+		for _, name := range strings.Split(value, ",") {
+			if name != "" {
+				q.Providers = append(q.Providers, name)
+			}
+		}
+	case "cat", "category":
+		for _, name := range strings.Split(value, ",") {
+			if name == "" {
+				continue
+			}
+			c, ok := ParseCategory(name)
+			if !ok {
+				return fmt.Errorf("unknown category %q, use one of %s", name, strings.Join(CategoryNames(), ", "))
+			}
+			q.Categories = append(q.Categories, c)
+		}
+	case "after":
+		t, err := parseDate(value)
+		if err != nil {
+			return err
+		}
+		q.After = t
+	case "before":
+		t, err := parseDate(value)
+		if err != nil {
+			return err
+		}
+		q.Before = t
+	}
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD, YYYY-MM or YYYY", value)
+}
+
+// ParseCategory returns the category with the given name, such as "movies",
+// "movies/hd" or "apps".
+func ParseCategory(name string) (interfaces.Category, bool) {
+	name = strings.ToLower(name)
+	if c, ok := categoryNames[name]; ok {
+		return c, true
+	}
+	for _, c := range interfaces.Categories() {
+		if strings.ToLower(c.String()) == name {
+			return c, true
+		}
+	}
+	return interfaces.CategoryUnknown, false
+}
+
+// CategoryNames returns the names of the categories that can be searched.
+func CategoryNames() []string {
+	var names []string
+	for name := range categoryNames {
+		names = append(names, name)
+	}
+	for _, c := range interfaces.Categories() {
+		names = append(names, strings.ToLower(c.String()))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// token is a word of a query, or a quoted phrase
+type token struct {
+	text    string
+	quoted  bool
+	negated bool
+}
+
+// split splits a query into words, keeping quoted phrases together. Quotes
+// can also be used in a qualifier's value, as in group:"some group".
+func split(s string) []token {
+	var tokens []token
+	var current strings.Builder
+	var t token
+	inQuotes, started := false, false
+	end := func() {
+		if started {
+			t.text = current.String()
+			tokens = append(tokens, t)
+		}
+		current.Reset()
+		t = token{}
+		started = false
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if !inQuotes && (current.Len() == 0 || current.String() == "-") {
+				t.quoted = true
+				t.negated = current.Len() > 0
+			}
+			inQuotes = !inQuotes
+			started = true
+		case !inQuotes && (r == ' ' || r == '\t'):
+			end()
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	end()
+
+	var words []token
+	for _, t := range tokens {
+		if t.quoted && strings.TrimPrefix(t.text, "-") == "" {
+			// empty phrases
+			continue
+		}
+		if !t.quoted && strings.HasPrefix(t.text, "-") && len(t.text) > 1 {
+			t.negated = true
+		}
+		words = append(words, t)
+	}
+	return words
+}
+
+// Empty returns true if the query has no qualifiers and checks nothing in
+// the results.
+func (q Query) Empty() bool {
+	return len(q.Phrases) == 0 && len(q.Providers) == 0 && len(q.Categories) == 0 &&
+		q.After.IsZero() && q.Before.IsZero() && len(q.excluded) == 0 && q.filter.Empty()
+}
+
+// Match returns true if the torrent satisfies the query's phrases, excluded
+// words and qualifiers. Words are left to the providers.
+func (q Query) Match(t interfaces.Torrent) bool {
+	title := strings.ToLower(t.Title)
+	for _, phrase := range q.Phrases {
+		if !strings.Contains(title, strings.ToLower(phrase)) {
+			return false
+		}
+	}
+	for _, word := range q.excluded {
+		if strings.Contains(title, word) {
+			return false
+		}
+	}
+	if len(q.Categories) > 0 && !q.matchCategory(t.Category) {
+		return false
+	}
+	if !q.After.IsZero() || !q.Before.IsZero() {
+		uploaded := t.UploadedTime()
+		if uploaded.IsZero() || uploaded.Before(q.After) || (!q.Before.IsZero() && !uploaded.Before(q.Before)) {
+			return false
+		}
+	}
+	return q.filter.Match(t)
+}
+
+func (q Query) matchCategory(c interfaces.Category) bool {
+	for _, category := range q.Categories {
+		if c.Matches(category) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns the torrents that match the query.
+func (q Query) Apply(torrents []interfaces.Torrent) []interfaces.Torrent {
+	if q.Empty() {
+		return torrents
+	}
+	var matched []interfaces.Torrent
+	for _, t := range torrents {
+		if q.Match(t) {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
+
+// SearchPage returns a page of the query's results in a provider, like the
+// client's SearchPage. Qualifiers the provider doesn't support are checked
+// in the results.
+func SearchPage(client interfaces.Client, q Query, page string) ([]interfaces.Torrent, string) {
+	var torrents []interfaces.Torrent
+	var next string
+	if searcher, ok := client.(Searcher); ok {
+		torrents, next = searcher.SearchQuery(q, page)
+	} else {
+		torrents, next = client.SearchPage(q.Text, page)
+	}
+	return q.Apply(torrents), next
+}
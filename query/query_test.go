@@ -0,0 +1,163 @@
+package query
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Query
+	}{
+		{"", Query{}},
+		{"   ", Query{}},
+		{"ubuntu desktop", Query{Text: "ubuntu desktop"}},
+		{`"exact phrase" ubuntu`, Query{Text: "exact phrase ubuntu", Phrases: []string{"exact phrase"}}},
+		{`ubuntu -beta -"release candidate"`, Query{Text: "ubuntu", excluded: []string{"beta", "release candidate"}}},
+		{`"" -"" -`, Query{Text: "-"}},
+		{"Re:Zero", Query{Text: "Re:Zero"}},
+		{"ubuntu provider:tpb,nyaa", Query{Text: "ubuntu", Providers: []string{"tpb", "nyaa"}}},
+		{"x Providers:tpb,", Query{Text: "x", Providers: []string{"tpb"}}},
+		{"x cat:apps,movies/hd", Query{Text: "x", Categories: []interfaces.Category{interfaces.CategoryPC, interfaces.CategoryMoviesHD}}},
+		{"x category:TV", Query{Text: "x", Categories: []interfaces.Category{interfaces.CategoryTV}}},
+		{"x after:2024-01 before:2024-02-15", Query{
+			Text:   "x",
+			After:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local),
+			Before: time.Date(2024, 2, 15, 0, 0, 0, 0, time.Local),
+		}},
+		{"x after:2023", Query{Text: "x", After: time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", tt.query, err)
+			continue
+		}
+		got.filter = tt.want.filter
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q)\n got %+v\nwant %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterFields(t *testing.T) {
+	q, err := Parse(`movie seeders:>20 group:"some group"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Text != "movie" || q.filter.Empty() {
+		t.Errorf("Parse() = %+v, want the filter fields out of the text", q)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{
+		"x -provider:tpb",
+		"x provider:",
+		"x cat:nothing",
+		"x cat:apps,nothing",
+		"x after:yesterday",
+		"x before:2024-13",
+		"x after:24-01-01",
+		"x seeders:many",
+	} {
+		if _, err := Parse(query); err == nil {
+			t.Errorf("Parse(%q) returned no error", query)
+		}
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	tests := map[string]interfaces.Category{
+		"movies":    interfaces.CategoryMovies,
+		"Movies/HD": interfaces.CategoryMoviesHD,
+		"anime":     interfaces.CategoryTVAnime,
+		"games":     interfaces.CategoryPCGames,
+	}
+	for name, want := range tests {
+		if got, ok := ParseCategory(name); !ok || got != want {
+			t.Errorf("ParseCategory(%q) = %s, %t, want %s", name, got, ok, want)
+		}
+	}
+	if _, ok := ParseCategory("nothing"); ok {
+		t.Error("ParseCategory of an unknown name succeeded")
+	}
+}
+
+func torrent(title string, category interfaces.Category, uploaded time.Time, seeders int) interfaces.Torrent {
+	t := interfaces.Torrent{Title: title, Category: category, Seeders: seeders, Release: release.Parse(title)}
+	if !uploaded.IsZero() {
+		t.Uploaded = strconv.FormatInt(uploaded.Unix(), 10)
+	}
+	return t
+}
+
+func TestMatch(t *testing.T) {
+	january := time.Date(2024, 1, 20, 12, 0, 0, 0, time.Local)
+	ubuntu := torrent("Ubuntu 22.04 Desktop Release Candidate", interfaces.CategoryPC, january, 30)
+	movie := torrent("Movie.Title.2023.1080p.WEB-DL.x264-GROUP", interfaces.CategoryMoviesHD, time.Time{}, 5)
+
+	tests := []struct {
+		query   string
+		torrent interfaces.Torrent
+		want    bool
+	}{
+		{"", ubuntu, true},
+		{"anything", ubuntu, true},
+		{`"desktop release"`, ubuntu, true},
+		{`"DESKTOP RELEASE"`, ubuntu, true},
+		{`"release desktop"`, ubuntu, false},
+		{"-candidate", ubuntu, false},
+		{`-"release candidate"`, ubuntu, false},
+		{`-"candidate release"`, ubuntu, true},
+		{"cat:apps", ubuntu, true},
+		{"cat:movies", ubuntu, false},
+		{"cat:movies", movie, true},
+		{"cat:movies/sd", movie, false},
+		{"cat:tv,movies", movie, true},
+		{"after:2024-01", ubuntu, true},
+		{"after:2024-02", ubuntu, false},
+		{"before:2024-01-20", ubuntu, false},
+		{"before:2024-01-21", ubuntu, true},
+		{"after:2020", movie, false},
+		{"provider:tpb", movie, true},
+		{"seeders:>20", ubuntu, true},
+		{"seeders:>20", movie, false},
+		{"res:1080p -cam", movie, true},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", tt.query, err)
+			continue
+		}
+		if got := q.Match(tt.torrent); got != tt.want {
+			t.Errorf("Parse(%q).Match(%q) = %t, want %t", tt.query, tt.torrent.Title, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyAndApply(t *testing.T) {
+	torrents := []interfaces.Torrent{
+		torrent("a beta", interfaces.CategoryPC, time.Time{}, 0),
+		torrent("b", interfaces.CategoryPC, time.Time{}, 0),
+	}
+
+	q, _ := Parse("only words")
+	if !q.Empty() || len(q.Apply(torrents)) != 2 {
+		t.Error("a query without qualifiers filtered the results")
+	}
+	q, _ = Parse("x -beta")
+	if q.Empty() {
+		t.Error("a query with an excluded word is empty")
+	}
+	if got := q.Apply(torrents); len(got) != 1 || got[0].Title != "b" {
+		t.Errorf("Apply() = %+v, want b", got)
+	}
+}
@@ -0,0 +1,214 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Info contains the metadata that can be extracted from a release name, such
+// as "Show.Name.S03E05.1080p.WEB-DL.DDP5.1.H.264-GROUP".
+// Fields that could not be found are left empty (or zero).
+type Info struct {
+	Resolution string
+	Source     string
+	Codec      string
+	HDR        string
+	Audio      string
+	Group      string
+	Season     int
+	Episode    int
+	Year       int
+}
+
+// a pattern and the canonical value it maps to
+type token struct {
+	pattern *regexp.Regexp
+	value   string
+}
+
+// delimiters used in release names, used to build word boundaries that also
+// consider '.', '_' and '-' as separators
+const (
+	before = `(?i)(?:^|[\s._\-\[\(])`
+	after  = `(?:$|[\s._\-\]\)])`
+)
+
+func newToken(pattern, value string) token {
+	return token{regexp.MustCompile(before + "(?:" + pattern + ")" + after), value}
+}
+
+var resolutions = []token{
+	newToken(`2160p|4k|uhd`, "2160p"),
+	newToken(`1080[pi]`, "1080p"),
+	newToken(`720p`, "720p"),
+	newToken(`576[pi]`, "576p"),
+	newToken(`480[pi]`, "480p"),
+}
+
+// order matters, the first match wins
+var sources = []token{
+	newToken(`remux|bdremux`, "Remux"),
+	newToken(`web[\s._\-]?dl|webdl`, "WEB-DL"),
+	newToken(`web[\s._\-]?rip`, "WEBRip"),
+	newToken(`web`, "WEB"),
+	newToken(`blu[\s._\-]?ray|bdrip|brrip|bd25|bd50`, "BluRay"),
+	newToken(`hdtv|pdtv|sdtv`, "HDTV"),
+	newToken(`dvd[\s._\-]?rip|dvdr|dvd[\s._\-]?5|dvd[\s._\-]?9|dvd`, "DVD"),
+	newToken(`hd[\s._\-]?cam|cam[\s._\-]?rip|cam`, "CAM"),
+	newToken(`hd[\s._\-]?ts|telesync|ts`, "TS"),
+	newToken(`hd[\s._\-]?tc|telecine|tc`, "TC"),
+	newToken(`dvd[\s._\-]?scr|screener|scr`, "SCR"),
+}
+
+var codecs = []token{
+	newToken(`x[\s._\-]?265|h[\s._\-]?265|hevc`, "x265"),
+	newToken(`x[\s._\-]?264|h[\s._\-]?264|avc`, "x264"),
+	newToken(`av1`, "AV1"),
+	newToken(`vp9`, "VP9"),
+	newToken(`xvid`, "XviD"),
+	newToken(`divx`, "DivX"),
+	newToken(`mpeg[\s._\-]?2`, "MPEG-2"),
+}
+
+var hdrs = []token{
+	newToken(`dv|dovi|dolby[\s._\-]?vision`, "DV"),
+	newToken(`hdr10\+|hdr10plus`, "HDR10+"),
+	newToken(`hdr10`, "HDR10"),
+	newToken(`hdr`, "HDR"),
+	newToken(`hlg`, "HLG"),
+}
+
+var audios = []token{
+	newToken(`atmos`, "Atmos"),
+	newToken(`truehd`, "TrueHD"),
+	newToken(`dts[\s._\-]?hd([\s._\-]?ma)?`, "DTS-HD"),
+	newToken(`dts[\s._\-]?x`, "DTS:X"),
+	newToken(`dts`, "DTS"),
+	newToken(`ddp[\s._\-]?[257]\.[01]|ddp|dd\+|e[\s._\-]?ac[\s._\-]?3`, "DDP"),
+	newToken(`dd[\s._\-]?[257]\.[01]|ac3`, "DD"),
+	newToken(`flac`, "FLAC"),
+	newToken(`aac([\s._\-]?[257]\.[01])?`, "AAC"),
+	newToken(`opus`, "Opus"),
+	newToken(`mp3`, "MP3"),
+}
+
+var (
+	seasonEpisodeRegex = regexp.MustCompile(before + `s(\d{1,2})[\s._\-]?e(\d{1,4})` + `(?:[\s._\-]?e\d{1,4})*` + after)
+	crossEpisodeRegex  = regexp.MustCompile(before + `(\d{1,2})x(\d{2,3})` + after)
+	seasonOnlyRegex    = regexp.MustCompile(before + `(?:s|season[\s._\-]?)(\d{1,2})` + after)
+	episodeOnlyRegex   = regexp.MustCompile(`(?i)(?:^|[\s._\[\(])(?:e|ep|episode[\s._\-]?)(\d{1,4})` + after)
+	animeEpisodeRegex  = regexp.MustCompile(`^\[[^\]]+\].*?\s-\s(\d{1,4})(?:v\d)?(?:\s|$)`)
+	yearRegex          = regexp.MustCompile(`(?:^|[\s._\-\[\(])((?:19|20)\d{2})` + after)
+	groupSuffixRegex   = regexp.MustCompile(`-([A-Za-z0-9]+)(?:\[[^\]]*\])?(?:\.(?:mkv|mp4|avi|m4v|wmv|ts))?\s*$`)
+	groupPrefixRegex   = regexp.MustCompile(`^\[([^\]]+)\]`)
+)
+
+// words that may appear after the last dash but are not release groups
+var notGroups = map[string]bool{
+	"dl": true, "rip": true, "web": true, "ray": true,
+	"264": true, "265": true, "ac3": true, "hd": true,
+}
+
+// Parse extracts all known metadata from a release name.
+func Parse(name string) Info {
+	info := Info{
+		Resolution: matchToken(name, resolutions),
+		Source:     matchToken(name, sources),
+		Codec:      matchToken(name, codecs),
+		HDR:        matchToken(name, hdrs),
+		Audio:      matchToken(name, audios),
+		Group:      parseGroup(name),
+		Year:       parseYear(name),
+	}
+	info.Season, info.Episode = parseSeasonEpisode(name)
+
+	return info
+}
+
+// SeasonEpisode returns a short representation of the season and episode,
+// such as "S03E05", "S03" or "E12". It is empty if neither is known.
+func (i Info) SeasonEpisode() string {
+	switch {
+	case i.Season > 0 && i.Episode > 0:
+		return fmt.Sprintf("S%02dE%02d", i.Season, i.Episode)
+	case i.Season > 0:
+		return fmt.Sprintf("S%02d", i.Season)
+	case i.Episode > 0:
+		return fmt.Sprintf("E%02d", i.Episode)
+	default:
+		return ""
+	}
+}
+
+// ResolutionHeight returns the vertical resolution as a number (1080 for
+// "1080p"), or 0 if the resolution is unknown.
+func (i Info) ResolutionHeight() int {
+	height, err := strconv.Atoi(strings.TrimSuffix(i.Resolution, "p"))
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+func matchToken(name string, tokens []token) string {
+	for _, t := range tokens {
+		if t.pattern.MatchString(name) {
+			return t.value
+		}
+	}
+	return ""
+}
+
+func parseSeasonEpisode(name string) (int, int) {
+	if match := seasonEpisodeRegex.FindStringSubmatch(name); match != nil {
+		return atoi(match[1]), atoi(match[2])
+	}
+	if match := crossEpisodeRegex.FindStringSubmatch(name); match != nil {
+		return atoi(match[1]), atoi(match[2])
+	}
+
+	season := 0
+	if match := seasonOnlyRegex.FindStringSubmatch(name); match != nil {
+		season = atoi(match[1])
+	}
+
+	episode := 0
+	if match := episodeOnlyRegex.FindStringSubmatch(name); match != nil {
+		episode = atoi(match[1])
+	} else if match := animeEpisodeRegex.FindStringSubmatch(name); match != nil {
+		episode = atoi(match[1])
+	}
+	return season, episode
+}
+
+// parseYear returns the last year found in the name, since titles may start
+// with a number that looks like a year ("2001 A Space Odyssey 1968").
+func parseYear(name string) int {
+	matches := yearRegex.FindAllStringSubmatchIndex(name, -1)
+	if len(matches) == 0 {
+		return 0
+	}
+	last := matches[len(matches)-1]
+	return atoi(name[last[2]:last[3]])
+}
+
+func parseGroup(name string) string {
+	name = strings.TrimSpace(name)
+	if match := groupSuffixRegex.FindStringSubmatch(name); match != nil {
+		if !notGroups[strings.ToLower(match[1])] {
+			return match[1]
+		}
+	}
+	// anime releases usually start with the group in brackets
+	if match := groupPrefixRegex.FindStringSubmatch(name); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,149 @@
+package release
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Info
+	}{
+		{
+			"Show.Name.S03E05.1080p.WEB-DL.DDP5.1.H.264-GROUP",
+			Info{Resolution: "1080p", Source: "WEB-DL", Codec: "x264", Audio: "DDP", Group: "GROUP", Season: 3, Episode: 5},
+		},
+		{
+			"Movie.Title.2019.2160p.UHD.BluRay.REMUX.HDR.HEVC.Atmos-EPSiLON",
+			Info{Resolution: "2160p", Source: "Remux", Codec: "x265", HDR: "HDR", Audio: "Atmos", Group: "EPSiLON", Year: 2019},
+		},
+		{
+			"Movie Title (2021) [1080p] [BluRay] [5.1] [YTS.MX]",
+			Info{Resolution: "1080p", Source: "BluRay", Year: 2021},
+		},
+		{
+			"Movie.Title.2022.1080p.WEBRip.x265.10bit.AAC5.1-RARBG",
+			Info{Resolution: "1080p", Source: "WEBRip", Codec: "x265", Audio: "AAC", Group: "RARBG", Year: 2022},
+		},
+		{
+			"Movie.Title.2023.2160p.WEB-DL.DV.HDR10+.DDP5.1.Atmos.H.265-FLUX",
+			Info{Resolution: "2160p", Source: "WEB-DL", Codec: "x265", HDR: "DV", Audio: "Atmos", Group: "FLUX", Year: 2023},
+		},
+		{
+			"Movie.Title.2023.2160p.WEB-DL.HDR10+.DDP5.1.H.265-FLUX",
+			Info{Resolution: "2160p", Source: "WEB-DL", Codec: "x265", HDR: "HDR10+", Audio: "DDP", Group: "FLUX", Year: 2023},
+		},
+		{
+			"Movie.Title.2018.1080p.BluRay.x264.DTS-HD.MA.7.1-FGT",
+			Info{Resolution: "1080p", Source: "BluRay", Codec: "x264", Audio: "DTS-HD", Group: "FGT", Year: 2018},
+		},
+		{
+			"Show Name S01 1080p BluRay x265 10bit",
+			Info{Resolution: "1080p", Source: "BluRay", Codec: "x265", Season: 1},
+		},
+		{
+			"Show.Name.Season.2.720p.HDTV.x264",
+			Info{Resolution: "720p", Source: "HDTV", Codec: "x264", Season: 2},
+		},
+		{
+			"show.name.3x07.hdtv.xvid-lol",
+			Info{Source: "HDTV", Codec: "XviD", Group: "lol", Season: 3, Episode: 7},
+		},
+		{
+			"Show.Name.S02E01E02.720p.HDTV.x264-KILLERS",
+			Info{Resolution: "720p", Source: "HDTV", Codec: "x264", Group: "KILLERS", Season: 2, Episode: 1},
+		},
+		{
+			"[SubsPlease] Anime Title - 07 (1080p) [ABCD1234].mkv",
+			Info{Resolution: "1080p", Group: "SubsPlease", Episode: 7},
+		},
+		{
+			"[Erai-raws] Anime Title - 1052 [720p][Multiple Subtitle]",
+			Info{Resolution: "720p", Group: "Erai-raws", Episode: 1052},
+		},
+		{
+			"Anime Title Episode 12 [1080p]",
+			Info{Resolution: "1080p", Episode: 12},
+		},
+		{
+			"2001 A Space Odyssey 1968 1080p BluRay",
+			Info{Resolution: "1080p", Source: "BluRay", Year: 1968},
+		},
+		{
+			"Movie.Title.2020.HDCAM.x264-NOGRP",
+			Info{Source: "CAM", Codec: "x264", Group: "NOGRP", Year: 2020},
+		},
+		{
+			"Movie Title 2020 HD-TS XviD",
+			Info{Source: "TS", Codec: "XviD", Year: 2020},
+		},
+		{
+			"Movie.Title.1999.DVDRip.DivX.AC3-WAF",
+			Info{Source: "DVD", Codec: "DivX", Audio: "DD", Group: "WAF", Year: 1999},
+		},
+		{
+			"Artist - Album (2015) [FLAC]",
+			Info{Audio: "FLAC", Year: 2015},
+		},
+		{
+			"Artist - Album (2010) MP3 320kbps",
+			Info{Audio: "MP3", Year: 2010},
+		},
+		{
+			"Movie.Title.2024.1080p.WEB.H264-GROUP",
+			Info{Resolution: "1080p", Source: "WEB", Codec: "x264", Group: "GROUP", Year: 2024},
+		},
+		{
+			"Movie.Title.2021.1080p.AMZN.WEB-DL.DDP5.1.H.264-NTb",
+			Info{Resolution: "1080p", Source: "WEB-DL", Codec: "x264", Audio: "DDP", Group: "NTb", Year: 2021},
+		},
+		{
+			"Movie Title 2021 720p WEB-DL",
+			Info{Resolution: "720p", Source: "WEB-DL", Year: 2021},
+		},
+		{
+			"Ubuntu 22.04.3 LTS Desktop amd64",
+			Info{},
+		},
+		{
+			"",
+			Info{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Parse(tt.name); got != tt.want {
+			t.Errorf("Parse(%q)\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSeasonEpisode(t *testing.T) {
+	tests := []struct {
+		info Info
+		want string
+	}{
+		{Info{Season: 3, Episode: 5}, "S03E05"},
+		{Info{Season: 12, Episode: 105}, "S12E105"},
+		{Info{Season: 1}, "S01"},
+		{Info{Episode: 7}, "E07"},
+		{Info{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.info.SeasonEpisode(); got != tt.want {
+			t.Errorf("%+v.SeasonEpisode() = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestResolutionHeight(t *testing.T) {
+	tests := map[string]int{
+		"2160p": 2160,
+		"1080p": 1080,
+		"480p":  480,
+		"":      0,
+	}
+	for resolution, want := range tests {
+		if got := (Info{Resolution: resolution}).ResolutionHeight(); got != want {
+			t.Errorf("ResolutionHeight() of %q = %d, want %d", resolution, got, want)
+		}
+	}
+}
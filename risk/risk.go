@@ -0,0 +1,312 @@
+package risk
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/inhies/go-bytesize"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+type Severity int
+
+const (
+	None Severity = iota
+	Low
+	Medium
+	High
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Low:
+		return "low"
+	case Medium:
+		return "medium"
+	case High:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// Flag is a single reason for a torrent to be considered suspicious.
+type Flag struct {
+	Severity Severity
+	Reason   string
+}
+
+// Assessment contains every flag raised for a torrent.
+type Assessment struct {
+	Flags []Flag
+	// FilesChecked is false if the torrent's files were not available, so
+	// file based checks were skipped
+	FilesChecked bool
+}
+
+// Config allows users to extend the built-in heuristics.
+type Config struct {
+	// UntrustedUploaders are uploaders whose torrents are always flagged
+	UntrustedUploaders []string
+	// Keywords are flagged when found in a torrent's title, in addition to
+	// the default ones
+	Keywords []string
+}
+
+// Assessor scores torrents using a set of heuristics.
+type Assessor struct {
+	untrustedUploaders map[string]bool
+	keywords           []string
+}
+
+// extensions that should never be found in a video release
+var executableExtensions = map[string]bool{
+	".exe": true, ".lnk": true, ".scr": true, ".bat": true, ".cmd": true,
+	".com": true, ".pif": true, ".msi": true, ".vbs": true, ".js": true,
+	".jar": true, ".ps1": true, ".hta": true, ".wsf": true,
+}
+
+var archiveExtensions = map[string]bool{
+	".rar": true, ".zip": true, ".7z": true, ".tar": true, ".gz": true,
+}
+
+var videoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true, ".m4v": true, ".wmv": true,
+	".mov": true, ".ts": true, ".webm": true, ".mpg": true,
+}
+
+// phrases used to give the password of a locked archive, such as "password:",
+// "the password is" or "rar password", rather than any mention of a password
+var passwordPattern = regexp.MustCompile(`password\s*(:|=|is\b)|\b(rar|zip|7z|archive|extraction|unlock)\s+password|password[- ]protected`)
+
+var defaultKeywords = []string{
+	"codec pack", "codecpack", "keygen", "activator",
+	"full movie online", "free download", "install to watch",
+}
+
+// minimum plausible size of a movie for each resolution, episodes are
+// expected to be at least a tenth of that
+var minimumSizes = map[string]bytesize.ByteSize{
+	"2160p": 2 * bytesize.GB,
+	"1080p": 700 * bytesize.MB,
+	"720p":  350 * bytesize.MB,
+	"576p":  200 * bytesize.MB,
+	"480p":  150 * bytesize.MB,
+}
+
+// maximum plausible size of a movie for each resolution, episodes are
+// expected to be at most a fifth of that
+var maximumSizes = map[string]bytesize.ByteSize{
+	"1080p": 60 * bytesize.GB,
+	"720p":  20 * bytesize.GB,
+	"576p":  10 * bytesize.GB,
+	"480p":  8 * bytesize.GB,
+}
+
+const (
+	// a swarm with at least this many seeders and no leechers is unlikely
+	suspiciousSeeders = 1000
+	// the sizes above are for a movie of this many minutes, they're scaled
+	// to the runtime of movies that have one
+	referenceRuntime = 120
+)
+
+func New(config Config) Assessor {
+	a := Assessor{
+		untrustedUploaders: make(map[string]bool),
+		keywords:           append([]string{}, defaultKeywords...),
+	}
+	for _, u := range config.UntrustedUploaders {
+		a.untrustedUploaders[strings.ToLower(u)] = true
+	}
+	for _, k := range config.Keywords {
+		a.keywords = append(a.keywords, strings.ToLower(k))
+	}
+	return a
+}
+
+// Assess runs every heuristic on the torrent. Checks that depend on the file
+// list are only run if the torrent's files have already been fetched.
+func (a Assessor) Assess(t interfaces.Torrent) Assessment {
+	var assessment Assessment
+
+	assessment.add(a.checkUploader(t)...)
+	assessment.add(checkSwarm(t)...)
+	assessment.add(a.checkKeywords(t)...)
+	assessment.add(checkSize(t)...)
+	assessment.add(checkDescription(t)...)
+	if t.Files != nil {
+		assessment.FilesChecked = true
+		assessment.add(checkFiles(t)...)
+	}
+
+	return assessment
+}
+
+func (a *Assessment) add(flags ...Flag) {
+	a.Flags = append(a.Flags, flags...)
+}
+
+// Severity returns the highest severity of all flags.
+func (a Assessment) Severity() Severity {
+	severity := None
+	for _, f := range a.Flags {
+		if f.Severity > severity {
+			severity = f.Severity
+		}
+	}
+	return severity
+}
+
+// Badge returns a short marker to be shown next to a torrent.
+func (a Assessment) Badge() string {
+	switch a.Severity() {
+	case High:
+		return "!!"
+	case Medium:
+		return "!"
+	case Low:
+		return "?"
+	default:
+		return ""
+	}
+}
+
+// Explain returns a human readable list of the reasons a torrent was flagged.
+// fetchKey is the key that fetches the torrent's files, mentioned while they
+// haven't been checked.
+func (a Assessment) Explain(fetchKey string) string {
+	if len(a.Flags) == 0 {
+		s := "No warnings"
+		if !a.FilesChecked {
+			s += " (files not checked yet, press " + fetchKey + " to fetch them)"
+		}
+		return s + "\n"
+	}
+
+	s := fmt.Sprintf("Warnings (risk: %s):\n", a.Severity())
+	for _, f := range a.Flags {
+		s += fmt.Sprintf("  [%s] %s\n", f.Severity, f.Reason)
+	}
+	if !a.FilesChecked {
+		s += "  Files not checked yet, press " + fetchKey + " to fetch them\n"
+	}
+	return s
+}
+
+func (a Assessor) checkUploader(t interfaces.Torrent) []Flag {
+	if t.Uploader == "" {
+		return nil
+	}
+	if a.untrustedUploaders[strings.ToLower(t.Uploader)] {
+		return []Flag{{High, fmt.Sprintf("uploader %q is in the untrusted list", t.Uploader)}}
+	}
+	if strings.EqualFold(t.Uploader, "anonymous") {
+		return []Flag{{Low, "uploaded anonymously"}}
+	}
+	return nil
+}
+
+func checkSwarm(t interfaces.Torrent) []Flag {
+	if t.Leechers == 0 && t.Seeders >= suspiciousSeeders {
+		return []Flag{{Medium, fmt.Sprintf("%d seeders but no leechers, the swarm may be faked", t.Seeders)}}
+	}
+	return nil
+}
+
+func (a Assessor) checkKeywords(t interfaces.Torrent) []Flag {
+	var flags []Flag
+	title := strings.ToLower(t.Title)
+	for _, k := range a.keywords {
+		if strings.Contains(title, k) {
+			flags = append(flags, Flag{Medium, fmt.Sprintf("title contains %q", k)})
+		}
+	}
+	if passwordPattern.MatchString(title) {
+		flags = append(flags, Flag{Medium, "title mentions a password, the content may be locked"})
+	}
+	return flags
+}
+
+func checkDescription(t interfaces.Torrent) []Flag {
+	if passwordPattern.MatchString(strings.ToLower(t.Description)) {
+		return []Flag{{High, "description mentions a password, the content may be locked"}}
+	}
+	return nil
+}
+
+// checkSize compares the torrent's size with the usual size of releases with
+// the same resolution and, for movies, the same runtime
+func checkSize(t interfaces.Torrent) []Flag {
+	resolution := t.Release.Resolution
+	size := bytesize.ByteSize(t.Size)
+	if resolution == "" || size == 0 {
+		return nil
+	}
+
+	isEpisode := t.Release.Episode > 0
+	minimum, maximum := minimumSizes[resolution], maximumSizes[resolution]
+	release := resolution + " release"
+	switch {
+	case isEpisode:
+		minimum /= 10
+		maximum /= 5
+	case t.Runtime > 0:
+		minimum = minimum * bytesize.ByteSize(t.Runtime) / referenceRuntime
+		maximum = maximum * bytesize.ByteSize(t.Runtime) / referenceRuntime
+		release = fmt.Sprintf("%s movie of %dh%02dm", resolution, t.Runtime/60, t.Runtime%60)
+	}
+
+	if size < minimum {
+		return []Flag{{Medium, fmt.Sprintf("%s is too small for a %s (expected at least %s)", size, release, minimum)}}
+	}
+	// a season pack can be as big as it wants
+	if maximum > 0 && size > maximum && (isEpisode || t.Release.Season == 0) {
+		return []Flag{{Low, fmt.Sprintf("%s is unusually big for a %s (expected at most %s)", size, release, maximum)}}
+	}
+	return nil
+}
+
+func checkFiles(t interfaces.Torrent) []Flag {
+	var flags []Flag
+	hasVideo := false
+	for _, f := range t.Files {
+		if videoExtensions[strings.ToLower(path.Ext(f.Name))] {
+			hasVideo = true
+		}
+	}
+	isVideo := hasVideo || t.Release.Resolution != "" || t.Release.Source != ""
+
+	for _, f := range t.Files {
+		name := strings.ToLower(f.Name)
+		ext := path.Ext(name)
+		switch {
+		case executableExtensions[ext] && isVideo:
+			flags = append(flags, Flag{High, fmt.Sprintf("video release contains executable file %q", f.Name)})
+		case archiveExtensions[ext] && isVideo && !hasVideo:
+			flags = append(flags, Flag{Medium, fmt.Sprintf("video is inside archive %q, which may be password protected", f.Name)})
+		case !videoExtensions[ext] && isPasswordFile(name):
+			flags = append(flags, Flag{High, fmt.Sprintf("file %q suggests the content is password protected", f.Name)})
+		}
+	}
+
+	if isVideo && !hasVideo && len(t.Files) > 0 && len(flags) == 0 {
+		flags = append(flags, Flag{Low, "release looks like a video but contains no video files"})
+	}
+	return flags
+}
+
+// isPasswordFile reports whether a file name looks like it gives out the
+// password of a locked archive, such as "password.txt" or "RAR Password.url".
+// Videos and other files that only have "password" in their title, such as
+// "Password.2019.1080p.nfo", aren't matched.
+func isPasswordFile(name string) bool {
+	stem := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	words := strings.Join(strings.FieldsFunc(stem, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+	return words == "password" || words == "passwords" || passwordPattern.MatchString(words)
+}
@@ -0,0 +1,241 @@
+package risk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
+
+func TestCheckDescription(t *testing.T) {
+	tests := map[string]bool{
+		"Password: 1234":                          true,
+		"The password is on our website":          true,
+		"password = visit site":                   true,
+		"Get the RAR password from the link":      true,
+		"Archive is password-protected":           true,
+		"Extraction password in the readme":       true,
+		"No password needed, enjoy":               false,
+		"Forgot your password? Reset it":          false,
+		"Season 2 of Password (the game show)":    false,
+		"Passwords, a documentary about security": false,
+		"": false,
+	}
+	for description, want := range tests {
+		flags := checkDescription(interfaces.Torrent{Description: description})
+		if got := len(flags) > 0; got != want {
+			t.Errorf("checkDescription(%q) = %+v, want flagged: %t", description, flags, want)
+		}
+	}
+}
+
+const (
+	mb = 1000 * 1000
+	gb = 1000 * mb
+)
+
+// reasons returns the reasons of the flags raised for a torrent
+func reasons(a Assessment) []string {
+	var r []string
+	for _, f := range a.Flags {
+		r = append(r, f.Reason)
+	}
+	return r
+}
+
+func TestCheckKeywords(t *testing.T) {
+	a := New(Config{Keywords: []string{"HDCAM"}})
+	tests := map[string]int{
+		"Some.Movie.2022.1080p.WEB-DL":                    0,
+		"Password.2019.1080p.BluRay":                      0,
+		"Password Game S01E01 720p":                       0,
+		"Some Movie 2022 Full Movie Online Free":          1,
+		"Some.Movie.2022.HDCAM":                           1,
+		"Some Movie 2022 + Codec Pack + Keygen":           2,
+		"Some Movie 2022 [RAR password: see readme]":      1,
+		"Some Movie 2022 (password protected) hdcam x264": 2,
+	}
+	for title, want := range tests {
+		flags := a.checkKeywords(interfaces.Torrent{Title: title})
+		if len(flags) != want {
+			t.Errorf("checkKeywords(%q) = %+v, want %d flags", title, flags, want)
+		}
+		for _, f := range flags {
+			if f.Severity != Medium {
+				t.Errorf("checkKeywords(%q) severity = %s, want medium", title, f.Severity)
+			}
+		}
+	}
+}
+
+func TestCheckUploader(t *testing.T) {
+	a := New(Config{UntrustedUploaders: []string{"BadGuy"}})
+	tests := map[string]Severity{
+		"":          None,
+		"someone":   None,
+		"badguy":    High,
+		"BADGUY":    High,
+		"Anonymous": Low,
+	}
+	for uploader, want := range tests {
+		var got Assessment
+		got.add(a.checkUploader(interfaces.Torrent{Uploader: uploader})...)
+		if got.Severity() != want {
+			t.Errorf("checkUploader(%q) = %+v, want %s", uploader, got.Flags, want)
+		}
+	}
+}
+
+func TestCheckSwarm(t *testing.T) {
+	tests := []struct {
+		seeders, leechers int
+		want              bool
+	}{
+		{5000, 0, true},
+		{suspiciousSeeders, 0, true},
+		{suspiciousSeeders - 1, 0, false},
+		{5000, 10, false},
+	}
+	for _, tt := range tests {
+		flags := checkSwarm(interfaces.Torrent{Seeders: tt.seeders, Leechers: tt.leechers})
+		if got := len(flags) > 0; got != tt.want {
+			t.Errorf("checkSwarm(%d seeders, %d leechers) = %+v, want flagged: %t", tt.seeders, tt.leechers, flags, tt.want)
+		}
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		size    int
+		runtime int
+		want    Severity
+	}{
+		{"unknown resolution", "Some.Movie.2022.WEB-DL", 10 * mb, 0, None},
+		{"unknown size", "Some.Movie.2022.1080p.WEB-DL", 0, 0, None},
+		{"plausible movie", "Some.Movie.2022.1080p.WEB-DL", 2 * gb, 0, None},
+		{"tiny movie", "Some.Movie.2022.1080p.WEB-DL", 100 * mb, 0, Medium},
+		{"huge movie", "Some.Movie.2022.720p.WEB-DL", 30 * gb, 0, Low},
+		{"no maximum for 2160p", "Some.Movie.2022.2160p.WEB-DL", 90 * gb, 0, None},
+		{"plausible episode", "Show.S01E02.1080p.WEB-DL", 300 * mb, 0, None},
+		{"tiny episode", "Show.S01E02.1080p.WEB-DL", 20 * mb, 0, Medium},
+		{"huge episode", "Show.S01E02.1080p.WEB-DL", 15 * gb, 0, Low},
+		{"season pack", "Show.S01.1080p.WEB-DL", 100 * gb, 0, None},
+		{"short movie", "Short.Movie.2022.1080p.WEB-DL", 400 * mb, 40, None},
+		{"long movie too small", "Long.Movie.2022.1080p.WEB-DL", 1 * gb, 240, Medium},
+		{"short movie too big", "Short.Movie.2022.720p.WEB-DL", 15 * gb, 60, Low},
+		{"long movie", "Long.Movie.2022.720p.WEB-DL", 30 * gb, 200, None},
+	}
+	for _, tt := range tests {
+		torrent := interfaces.Torrent{
+			Title:   tt.title,
+			Size:    tt.size,
+			Runtime: tt.runtime,
+			Release: release.Parse(tt.title),
+		}
+		var got Assessment
+		got.add(checkSize(torrent)...)
+		if got.Severity() != tt.want {
+			t.Errorf("%s: checkSize() = %+v, want %s", tt.name, got.Flags, tt.want)
+		}
+	}
+}
+
+func TestCheckSizeMentionsRuntime(t *testing.T) {
+	torrent := interfaces.Torrent{Size: 500 * mb, Runtime: 150, Release: release.Parse("Movie.2022.1080p")}
+	flags := checkSize(torrent)
+	if len(flags) != 1 || !strings.Contains(flags[0].Reason, "1080p movie of 2h30m") {
+		t.Errorf("checkSize() = %+v, want the runtime in the reason", flags)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		files []string
+		want  Severity
+	}{
+		{"video", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.mkv", "Movie.nfo", "Subs/English.srt"}, None},
+		{"executable", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.mkv", "Setup.exe"}, High},
+		{"shortcut", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.mkv", "Play Movie.LNK"}, High},
+		{"executable in software", "Some.Program.v1.2", []string{"setup.exe", "readme.txt"}, None},
+		{"archive", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.rar"}, Medium},
+		{"archive next to the video", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.mkv", "Extras.zip"}, None},
+		{"no video", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.txt"}, Low},
+		{"password file", "Movie.2022.1080p.WEB-DL", []string{"Movie.2022.1080p.WEB-DL.mkv", "PASSWORD.txt"}, High},
+		{"archive password file", "Some.Album.2022", []string{"track01.flac", "Info/RAR_Password.url"}, High},
+		{"password movie", "Password.2019.1080p.BluRay", []string{"Password.2019.1080p.BluRay.mkv", "Password.2019.1080p.BluRay.nfo", "Password.2019.srt"}, None},
+		{"password in a folder name", "Password.Game.S01.720p", []string{"Password Game/Password.Game.S01E01.720p.mkv"}, None},
+	}
+	for _, tt := range tests {
+		torrent := interfaces.Torrent{Title: tt.title, Release: release.Parse(tt.title)}
+		for _, name := range tt.files {
+			torrent.Files = append(torrent.Files, interfaces.TorrentFile{Name: name})
+		}
+		var got Assessment
+		got.add(checkFiles(torrent)...)
+		if got.Severity() != tt.want {
+			t.Errorf("%s: checkFiles() = %+v, want %s", tt.name, got.Flags, tt.want)
+		}
+	}
+}
+
+func TestAssess(t *testing.T) {
+	a := New(Config{UntrustedUploaders: []string{"badguy"}})
+	torrent := interfaces.Torrent{
+		Title:    "Movie.2022.1080p.WEB-DL",
+		Uploader: "badguy",
+		Size:     2 * gb,
+		Seeders:  10,
+		Leechers: 5,
+	}
+	torrent.Release = release.Parse(torrent.Title)
+
+	got := a.Assess(torrent)
+	if got.FilesChecked || got.Severity() != High || len(got.Flags) != 1 {
+		t.Errorf("Assess() = %+v, want a single high flag and no files checked", got)
+	}
+
+	torrent.Uploader = ""
+	torrent.Files = []interfaces.TorrentFile{{Name: "Movie.2022.1080p.WEB-DL.mkv"}}
+	got = a.Assess(torrent)
+	if !got.FilesChecked || len(got.Flags) != 0 || got.Badge() != "" {
+		t.Errorf("Assess() = %+v, want no flags and the files checked", got)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name       string
+		assessment Assessment
+		want       string
+	}{
+		{"no flags", Assessment{FilesChecked: true}, "No warnings\n"},
+		{"no flags, files missing", Assessment{}, "No warnings (files not checked yet, press f to fetch them)\n"},
+		{
+			"flags",
+			Assessment{FilesChecked: true, Flags: []Flag{{Low, "uploaded anonymously"}, {High, "bad file"}}},
+			"Warnings (risk: high):\n  [low] uploaded anonymously\n  [high] bad file\n",
+		},
+		{
+			"flags, files missing",
+			Assessment{Flags: []Flag{{Medium, "too small"}}},
+			"Warnings (risk: medium):\n  [medium] too small\n  Files not checked yet, press f to fetch them\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.assessment.Explain("f"); got != tt.want {
+			t.Errorf("%s: Explain() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	badges := map[Severity]string{None: "", Low: "?", Medium: "!", High: "!!"}
+	for severity, want := range badges {
+		a := Assessment{Flags: []Flag{{severity, "reason"}}}
+		if got := a.Badge(); got != want {
+			t.Errorf("Badge() of a %s flag = %q, want %q", severity, got, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/feed"
+	"github.com/ismaelpadilla/gotorrent/filter"
+)
+
+// handleFeed serves GET /feed?q=<query>[&provider=<name>,...][&filter=<filter>]
+// as an RSS feed
+func (s *server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	params := r.URL.Query()
+	q := strings.TrimSpace(params.Get("q"))
+	if q == "" {
+		writeJSONError(w, http.StatusBadRequest, "missing parameter q")
+		return
+	}
+	f, err := filter.Parse(params.Get("filter"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	providers, err := s.providersParam(params.Get("provider"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	torrents := f.Apply(s.search(providers, q))
+
+	base := s.baseURL(r)
+	apiKey := params.Get("apikey")
+	options := feed.Options{
+		Title:       feed.Title(q),
+		Link:        base + r.URL.RequestURI(),
+		Description: feed.Description(q, len(torrents)),
+		// enclosures point to this server's .torrent cache, feed readers
+		// can't send headers so the API key goes in the URL
+		TorrentURL: func(infoHash string) string {
+			u := base + "/torrents/" + infoHash + ".torrent"
+			if apiKey != "" {
+				u += "?apikey=" + url.QueryEscape(apiKey)
+			}
+			return u
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	if err := feed.Write(w, options, torrents); err != nil {
+		s.logger.Printf("writing feed for %q failed: %v", q, err)
+	}
+}
+
+// baseURL returns the scheme and host the request was sent to. The scheme
+// sent by a trusted proxy in X-Forwarded-Proto takes precedence.
+func (s *server) baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); (forwarded == "http" || forwarded == "https") && s.fromTrustedProxy(r) {
+		scheme = forwarded
+	}
+	return scheme + "://" + r.Host
+}
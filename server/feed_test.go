@@ -0,0 +1,53 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBaseURL(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.1", "192.168.0.0/16", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{trustedProxies: proxies}
+
+	tests := []struct {
+		remote    string
+		forwarded string
+		tls       bool
+		want      string
+	}{
+		{"10.0.0.1:1234", "https", false, "https://example.com"},
+		{"192.168.3.4:1234", "https", false, "https://example.com"},
+		{"[::1]:1234", "https", false, "https://example.com"},
+		// other clients can't change the scheme
+		{"10.0.0.2:1234", "https", false, "http://example.com"},
+		{"10.0.0.2:1234", "http", true, "https://example.com"},
+		{"10.0.0.1:1234", "", true, "https://example.com"},
+		{"10.0.0.1:1234", "javascript", false, "http://example.com"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://example.com/feed?q=x", nil)
+		r.RemoteAddr = tt.remote
+		if tt.forwarded != "" {
+			r.Header.Set("X-Forwarded-Proto", tt.forwarded)
+		}
+		if tt.tls {
+			r.TLS = &tls.ConnectionState{}
+		}
+		if got := s.baseURL(r); got != tt.want {
+			t.Errorf("baseURL() from %s with %q = %q, want %q", tt.remote, tt.forwarded, got, tt.want)
+		}
+	}
+}
+
+func TestParseProxies(t *testing.T) {
+	for _, address := range []string{"proxy", "10.0.0.0/33", ""} {
+		if _, err := ParseProxies([]string{address}); err == nil {
+			t.Errorf("ParseProxies(%q) returned no error", address)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/torrentcache"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+type torrentJSON struct {
+	Provider   string      `json:"provider"`
+	ID         string      `json:"id"`
+	Title      string      `json:"title"`
+	InfoHash   string      `json:"info_hash"`
+	MagnetLink string      `json:"magnet_link"`
+	Size       int         `json:"size"`
+	Seeders    int         `json:"seeders"`
+	Leechers   int         `json:"leechers"`
+	Uploaded   *time.Time  `json:"uploaded,omitempty"`
+	Uploader   string      `json:"uploader,omitempty"`
+	Category   int         `json:"category"`
+	Release    releaseJSON `json:"release"`
+	IMDbID     string      `json:"imdb_id,omitempty"`
+	Runtime    int         `json:"runtime,omitempty"`
+	Genres     []string    `json:"genres,omitempty"`
+}
+
+type releaseJSON struct {
+	Resolution string `json:"resolution,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Codec      string `json:"codec,omitempty"`
+	HDR        string `json:"hdr,omitempty"`
+	Audio      string `json:"audio,omitempty"`
+	Group      string `json:"group,omitempty"`
+	Season     int    `json:"season,omitempty"`
+	Episode    int    `json:"episode,omitempty"`
+	Year       int    `json:"year,omitempty"`
+}
+
+type fileJSON struct {
+	Name string `json:"name"`
+	Size int    `json:"size"`
+}
+
+type sendRequest struct {
+	MagnetLink string `json:"magnet_link"`
+	InfoHash   string `json:"info_hash"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+// rest wraps a REST handler with CORS headers and API key authentication
+func (s *server) rest(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.setCORSHeaders(w, r)
+		// preflight requests don't carry credentials
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		key := r.Header.Get("X-Api-Key")
+		if key == "" {
+			key = r.URL.Query().Get("apikey")
+		}
+		if !s.validAPIKey(key) {
+			writeJSONError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *server) setCORSHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+	for _, allowed := range s.corsOrigins {
+		if allowed == "*" || allowed == origin {
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Api-Key")
+			w.Header().Add("Vary", "Origin")
+			return
+		}
+	}
+}
+
+// handleSearch serves GET /search?q=<query>[&provider=<name>,...]
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeJSONError(w, http.StatusBadRequest, "missing parameter q")
+		return
+	}
+
+	providers, err := s.providersParam(r.URL.Query().Get("provider"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	torrents := s.search(providers, q)
+	results := make([]torrentJSON, len(torrents))
+	for i, t := range torrents {
+		results[i] = toJSON(t)
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// handleTorrents serves GET /torrents/<provider>/description?id=<id>,
+// GET /torrents/<provider>/files?id=<id> and GET /torrents/<hash>.torrent.
+// The ID is a parameter as IDs can have slashes, which the mux would clean
+// out of the path.
+func (s *server) handleTorrents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/torrents/"), "/"), "/")
+	switch {
+	case len(parts) == 1 && strings.HasSuffix(parts[0], ".torrent"):
+		s.serveTorrentFile(w, strings.TrimSuffix(parts[0], ".torrent"))
+	case len(parts) == 2 && (parts[1] == "description" || parts[1] == "files"):
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			writeJSONError(w, http.StatusBadRequest, "missing parameter id")
+			return
+		}
+		p, err := s.provider(parts[0])
+		if err != nil {
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		torrent := interfaces.Torrent{Client: p, ID: id}
+
+		err = clients.Recover(func() {
+			if parts[1] == "description" {
+				writeJSON(w, http.StatusOK, map[string]string{"description": p.FetchTorrentDescription(torrent)})
+				return
+			}
+
+			files := p.FetchTorrentFiles(torrent)
+			results := make([]fileJSON, len(files))
+			for i, f := range files {
+				results[i] = fileJSON{Name: f.Name, Size: f.Size}
+			}
+			writeJSON(w, http.StatusOK, results)
+		})
+		if err != nil {
+			s.logger.Printf("%s: fetching %s of %s failed: %v", p.Name(), parts[1], id, err)
+			writeJSONError(w, http.StatusBadGateway, "provider request failed")
+		}
+	default:
+		writeJSONError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *server) serveTorrentFile(w http.ResponseWriter, infoHash string) {
+	if !torrentcache.ValidInfoHash(infoHash) {
+		writeJSONError(w, http.StatusBadRequest, "invalid info-hash")
+		return
+	}
+
+	data, err := s.cache.Get(infoHash)
+	if err != nil {
+		s.logger.Printf("downloading .torrent file for %s failed: %v", infoHash, err)
+		writeJSONError(w, http.StatusBadGateway, "could not download .torrent file")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-bittorrent")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+strings.ToLower(infoHash)+`.torrent"`)
+	_, _ = w.Write(data)
+}
+
+// handleSend serves POST /send, which hands a magnet link to the torrent
+// client
+func (s *server) handleSend(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	// browsers send cross-origin form posts without a preflight request,
+	// requiring JSON makes them preflighted and subject to CORS
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeJSONError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return
+	}
+
+	var request sendRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&request); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	magnetLink := request.MagnetLink
+	switch {
+	case magnetLink != "" && !strings.HasPrefix(magnetLink, "magnet:?"):
+		writeJSONError(w, http.StatusBadRequest, "invalid magnet link")
+		return
+	case magnetLink == "" && torrentcache.ValidInfoHash(request.InfoHash):
+		magnetLink = "magnet:?xt=urn:btih:" + request.InfoHash
+	case magnetLink == "":
+		writeJSONError(w, http.StatusBadRequest, "magnet_link or info_hash is required")
+		return
+	}
+
+	if err := s.downloader.Send(magnetLink); err != nil {
+		s.logger.Printf("sending magnet link failed: %v", err)
+		writeJSONError(w, http.StatusBadGateway, "could not send magnet link to the torrent client")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "sent"})
+}
+
+func (s *server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	s.setCORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPI)
+}
+
+func toJSON(t interfaces.Torrent) torrentJSON {
+	result := torrentJSON{
+		ID:         t.ID,
+		Title:      t.Title,
+		InfoHash:   strings.ToLower(t.InfoHash),
+		MagnetLink: t.MagnetLink,
+		Size:       t.Size,
+		Seeders:    t.Seeders,
+		Leechers:   t.Leechers,
+		Uploader:   t.Uploader,
+		Category:   int(t.Category),
+		Release: releaseJSON{
+			Resolution: t.Release.Resolution,
+			Source:     t.Release.Source,
+			Codec:      t.Release.Codec,
+			HDR:        t.Release.HDR,
+			Audio:      t.Release.Audio,
+			Group:      t.Release.Group,
+			Season:     t.Release.Season,
+			Episode:    t.Release.Episode,
+			Year:       t.Release.Year,
+		},
+		IMDbID:  t.IMDbID,
+		Runtime: t.Runtime,
+		Genres:  t.Genres,
+	}
+	if t.Client != nil {
+		result.Provider = t.Client.Name()
+	}
+	if uploaded := t.UploadedTime(); !uploaded.IsZero() {
+		result.Uploaded = &uploaded
+	}
+	return result
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorJSON{Error: message})
+}
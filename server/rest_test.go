@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// detailProvider returns details named after the torrent's ID, and fails
+// for the ID "fail"
+type detailProvider struct {
+	fakeProvider
+}
+
+func (p detailProvider) FetchTorrentDescription(t interfaces.Torrent) string {
+	if t.ID == "fail" {
+		panic("request failed")
+	}
+	return "description of " + t.ID
+}
+
+func (p detailProvider) FetchTorrentFiles(t interfaces.Torrent) []interfaces.TorrentFile {
+	return []interfaces.TorrentFile{{Name: t.ID, Size: 1}}
+}
+
+func newTestServer(config Config) http.Handler {
+	config.Logger = log.New(io.Discard, "", 0)
+	return New(config)
+}
+
+func get(handler http.Handler, target string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	return recorder
+}
+
+func TestTorrentDetails(t *testing.T) {
+	handler := newTestServer(Config{Providers: []interfaces.Client{detailProvider{}}})
+
+	// 1337x and EZTV IDs have slashes, RSS IDs are links
+	for _, id := range []string{"5123456/Some-Name/", "https://example.com/t?id=1&x=y", "42"} {
+		recorder := get(handler, "/torrents/fake/description?id="+url.QueryEscape(id))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("description of %q: status %d, %s", id, recorder.Code, recorder.Body)
+			continue
+		}
+		var body map[string]string
+		if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body["description"] != "description of "+id {
+			t.Errorf("description = %q, want that of %q", body["description"], id)
+		}
+
+		recorder = get(handler, "/torrents/fake/files?id="+url.QueryEscape(id))
+		var files []fileJSON
+		if err := json.Unmarshal(recorder.Body.Bytes(), &files); err != nil {
+			t.Fatalf("%v: %s", err, recorder.Body)
+		}
+		if len(files) != 1 || files[0].Name != id {
+			t.Errorf("files = %+v, want those of %q", files, id)
+		}
+	}
+}
+
+func TestTorrentDetailsErrors(t *testing.T) {
+	handler := newTestServer(Config{Providers: []interfaces.Client{detailProvider{}}})
+
+	tests := map[string]int{
+		"/torrents/fake/description":         http.StatusBadRequest,
+		"/torrents/fake/description?id=fail": http.StatusBadGateway,
+		"/torrents/other/files?id=1":         http.StatusNotFound,
+		"/torrents/fake/1/description":       http.StatusNotFound,
+		"/torrents/fake/summary?id=1":        http.StatusNotFound,
+		"/torrents/not-a-hash.torrent":       http.StatusBadRequest,
+	}
+	for target, want := range tests {
+		if recorder := get(handler, target); recorder.Code != want {
+			t.Errorf("%s: status %d, want %d", target, recorder.Code, want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	provider := fakeProvider{pages: [][]string{{"a", "b"}}}
+	handler := newTestServer(Config{Providers: []interfaces.Client{provider}})
+
+	recorder := get(handler, "/search?q=ubuntu")
+	var results []torrentJSON
+	if err := json.Unmarshal(recorder.Body.Bytes(), &results); err != nil {
+		t.Fatalf("%v: %s", err, recorder.Body)
+	}
+	if len(results) != 2 || results[0].Title != "a" {
+		t.Errorf("results = %+v, want a and b", results)
+	}
+
+	if recorder := get(handler, "/search"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("search without query: status %d", recorder.Code)
+	}
+	if recorder := get(handler, "/search?q=x&provider=other"); recorder.Code != http.StatusBadRequest {
+		t.Errorf("search of an unknown provider: status %d", recorder.Code)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	handler := newTestServer(Config{APIKey: "secret", Providers: []interfaces.Client{detailProvider{}}})
+
+	if recorder := get(handler, "/torrents/fake/description?id=1"); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("request without API key: status %d", recorder.Code)
+	}
+	if recorder := get(handler, "/torrents/fake/description?id=1&apikey=secret"); recorder.Code != http.StatusOK {
+		t.Errorf("request with API key: status %d", recorder.Code)
+	}
+}
+
+func TestSendContentType(t *testing.T) {
+	handler := newTestServer(Config{})
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/send", strings.NewReader("magnet_link=magnet:?xt=urn:btih:abc"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("form post: status %d, want %d", recorder.Code, http.StatusUnsupportedMediaType)
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/downloader"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/torrentcache"
+)
+
+// Config configures the server.
+type Config struct {
+	// Providers are the clients exposed by the server
+	Providers []interfaces.Client
+	// APIKey must be sent by clients if it is not empty
+	APIKey string
+	// CORSOrigins are the origins allowed to call the REST API from a
+	// browser, "*" allows any origin
+	CORSOrigins []string
+	// TrustedProxies are the networks of the reverse proxies whose
+	// X-Forwarded-Proto header is honoured
+	TrustedProxies []*net.IPNet
+	Downloader     downloader.Downloader
+	Cache          *torrentcache.Cache
+	Logger         *log.Logger
+}
+
+type server struct {
+	providers      []interfaces.Client
+	apiKey         string
+	corsOrigins    []string
+	trustedProxies []*net.IPNet
+	downloader     downloader.Downloader
+	cache          *torrentcache.Cache
+	logger         *log.Logger
+}
+
+// New returns a handler exposing the configured providers through a Torznab
+// API, under /torznab/api for all providers and /torznab/<provider>/api for
+// a single one, and through a JSON REST API described in /openapi.json.
+func New(config Config) http.Handler {
+	s := &server{
+		providers:      config.Providers,
+		apiKey:         config.APIKey,
+		corsOrigins:    config.CORSOrigins,
+		trustedProxies: config.TrustedProxies,
+		downloader:     config.Downloader,
+		cache:          config.Cache,
+		logger:         config.Logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/torznab/", s.handleTorznab)
+	mux.HandleFunc("/search", s.rest(s.handleSearch))
+	mux.HandleFunc("/torrents/", s.rest(s.handleTorrents))
+	mux.HandleFunc("/send", s.rest(s.handleSend))
+	mux.HandleFunc("/feed", s.rest(s.handleFeed))
+	mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	return mux
+}
+
+// ParseProxies parses the addresses of trusted proxies, either IPs or
+// networks in CIDR notation such as 10.0.0.0/8.
+func ParseProxies(addresses []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, address := range addresses {
+		if ip := net.ParseIP(address); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", address)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// fromTrustedProxy returns true if the request was sent by a trusted proxy
+func (s *server) fromTrustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range s.trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// provider returns the provider with the given name
+func (s *server) provider(name string) (interfaces.Client, error) {
+	for _, p := range s.providers {
+		if p.Name() == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown provider %q", name)
+}
+
+// providersParam returns the providers listed in a comma separated request
+// parameter, or every provider if it is empty
+func (s *server) providersParam(names string) ([]interfaces.Client, error) {
+	if names == "" {
+		return s.providers, nil
+	}
+	var providers []interfaces.Client
+	for _, name := range strings.Split(names, ",") {
+		p, err := s.provider(name)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+// latest lists the latest torrents of every provider that can list them.
+// Providers that fail are logged and skipped.
+func (s *server) latest(providers []interfaces.Client) []interfaces.Torrent {
+	torrents, errs := clients.Latest(providers)
+	for _, err := range errs {
+		s.logger.Printf("listing the latest torrents failed: %v", err)
+	}
+	return torrents
+}
+
+// search runs the query in every provider at the same time. Providers that
+// fail are logged and skipped.
+func (s *server) search(providers []interfaces.Client, query string) []interfaces.Torrent {
+	torrents, errs := clients.Search(providers, query)
+	for _, err := range errs {
+		s.logger.Printf("search for %q failed: %v", query, err)
+	}
+	return torrents
+}
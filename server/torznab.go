@@ -0,0 +1,405 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/release"
+)
+
+const (
+	torznabNamespace = "http://torznab.com/schemas/2015/feed"
+	atomNamespace    = "http://www.w3.org/2005/Atom"
+	defaultLimit     = 100
+	maxLimit         = 100
+	// maxPages is how many pages of a provider's results are fetched at most
+	// to reach the requested offset
+	maxPages = 10
+)
+
+// Torznab error codes
+const (
+	errorIncorrectCredentials = 100
+	errorMissingParameter     = 200
+	errorNoSuchFunction       = 202
+)
+
+type torznabError struct {
+	XMLName     xml.Name `xml:"error"`
+	Code        int      `xml:"code,attr"`
+	Description string   `xml:"description,attr"`
+}
+
+type caps struct {
+	XMLName    xml.Name       `xml:"caps"`
+	Server     capsServer     `xml:"server"`
+	Limits     capsLimits     `xml:"limits"`
+	Searching  capsSearching  `xml:"searching"`
+	Categories []capsCategory `xml:"categories>category"`
+}
+
+type capsServer struct {
+	Title string `xml:"title,attr"`
+}
+
+type capsLimits struct {
+	Max     int `xml:"max,attr"`
+	Default int `xml:"default,attr"`
+}
+
+type capsSearching struct {
+	Search      capsSearch `xml:"search"`
+	TVSearch    capsSearch `xml:"tv-search"`
+	MovieSearch capsSearch `xml:"movie-search"`
+}
+
+type capsSearch struct {
+	Available       string `xml:"available,attr"`
+	SupportedParams string `xml:"supportedParams,attr"`
+}
+
+type capsCategory struct {
+	ID      int            `xml:"id,attr"`
+	Name    string         `xml:"name,attr"`
+	Subcats []capsCategory `xml:"subcat,omitempty"`
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Torznab string     `xml:"xmlns:torznab,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Description string    `xml:"description"`
+	Link        string    `xml:"link"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	GUID        string        `xml:"guid"`
+	Link        string        `xml:"link"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Size        int           `xml:"size"`
+	Category    []int         `xml:"category"`
+	Enclosure   rssEnclosure  `xml:"enclosure"`
+	Attributes  []torznabAttr `xml:"torznab:attr"`
+	Description string        `xml:"description,omitempty"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type torznabAttr struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// handleTorznab serves /torznab/api and /torznab/<provider>/api
+func (s *server) handleTorznab(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/torznab/"), "/")
+
+	providers := s.providers
+	switch parts := strings.Split(path, "/"); {
+	case path == "api":
+	case len(parts) == 2 && parts[1] == "api":
+		p, err := s.provider(parts[0])
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		providers = []interfaces.Client{p}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	query := r.URL.Query()
+	if !s.validAPIKey(query.Get("apikey")) {
+		writeTorznabError(w, errorIncorrectCredentials, "Incorrect user credentials")
+		return
+	}
+
+	switch query.Get("t") {
+	case "caps":
+		writeXML(w, capabilities())
+	case "search", "tvsearch", "tv-search", "movie", "movie-search":
+		s.torznabSearch(w, r, providers)
+	case "":
+		writeTorznabError(w, errorMissingParameter, "Missing parameter (t)")
+	default:
+		writeTorznabError(w, errorNoSuchFunction, "No such function")
+	}
+}
+
+func (s *server) validAPIKey(key string) bool {
+	if s.apiKey == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(key), []byte(s.apiKey)) == 1
+}
+
+func (s *server) torznabSearch(w http.ResponseWriter, r *http.Request, providers []interfaces.Client) {
+	params := r.URL.Query()
+
+	q := strings.TrimSpace(params.Get("q"))
+	season, _ := strconv.Atoi(strings.TrimPrefix(strings.ToLower(params.Get("season")), "s"))
+	episode, _ := strconv.Atoi(strings.TrimPrefix(strings.ToLower(params.Get("ep")), "e"))
+	imdbID := params.Get("imdbid")
+	if imdbID != "" && !strings.HasPrefix(imdbID, "tt") {
+		imdbID = "tt" + imdbID
+	}
+
+	// providers search by keywords, so season and episode are added to the
+	// query and checked again in the results
+	if q == "" && imdbID != "" {
+		q = imdbID
+	}
+	switch {
+	case season > 0 && episode > 0:
+		q += fmt.Sprintf(" S%02dE%02d", season, episode)
+	case season > 0:
+		q += fmt.Sprintf(" S%02d", season)
+	}
+
+	categories, err := parseCategories(params.Get("cat"))
+	if err != nil {
+		writeTorznabError(w, errorMissingParameter, err.Error())
+		return
+	}
+
+	offset, _ := strconv.Atoi(params.Get("offset"))
+	limit, err := strconv.Atoi(params.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	keep := func(t interfaces.Torrent) bool {
+		return (season == 0 || t.Release.Season == season) &&
+			(episode == 0 || t.Release.Episode == episode) &&
+			matchesCategories(t.Category, categories)
+	}
+	var torrents []interfaces.Torrent
+	if q == "" {
+		// Sonarr and Radarr test the indexer and read its RSS feed without
+		// a query, the latest torrents are returned where possible
+		for _, t := range s.latest(providers) {
+			if keep(t) {
+				torrents = append(torrents, t)
+			}
+		}
+	} else {
+		torrents = s.searchPages(providers, q, keep, offset+limit)
+	}
+	torrents = paginate(torrents, offset, limit)
+
+	writeXML(w, toRSS(torrents))
+}
+
+// searchPages runs the query in every provider at the same time, fetching
+// the next pages of their results until want results are kept or there are
+// no more. Providers that fail are logged and skipped.
+func (s *server) searchPages(providers []interfaces.Client, q string, keep func(interfaces.Torrent) bool, want int) []interfaces.Torrent {
+	pages := make([]string, len(providers))
+	searching := providers
+	var torrents []interfaces.Torrent
+	for round := 0; round < maxPages && len(searching) > 0 && len(torrents) < want; round++ {
+		results := make([][]interfaces.Torrent, len(searching))
+		next := make([]string, len(searching))
+		var wg sync.WaitGroup
+		for i, p := range searching {
+			wg.Add(1)
+			go func(i int, p interfaces.Client, page string) {
+				defer wg.Done()
+				err := clients.Recover(func() {
+					results[i], next[i] = p.SearchPage(q, page)
+				})
+				if err != nil {
+					s.logger.Printf("search for %q failed: %s: %v", q, p.Name(), err)
+				}
+			}(i, p, pages[i])
+		}
+		wg.Wait()
+
+		var more []interfaces.Client
+		var morePages []string
+		for i, p := range searching {
+			for _, t := range results[i] {
+				if keep(t) {
+					torrents = append(torrents, t)
+				}
+			}
+			if next[i] != "" {
+				more = append(more, p)
+				morePages = append(morePages, next[i])
+			}
+		}
+		searching, pages = more, morePages
+	}
+	return torrents
+}
+
+func parseCategories(value string) ([]interfaces.Category, error) {
+	var categories []interfaces.Category
+	for _, c := range strings.Split(value, ",") {
+		if c == "" {
+			continue
+		}
+		id, err := strconv.Atoi(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category %q", c)
+		}
+		categories = append(categories, interfaces.Category(id))
+	}
+	return categories, nil
+}
+
+// matchesCategories returns true if there are no categories to match, or if
+// category is one of them. Torrents with an unknown category are kept.
+func matchesCategories(category interfaces.Category, categories []interfaces.Category) bool {
+	if len(categories) == 0 || category == interfaces.CategoryUnknown {
+		return true
+	}
+	for _, c := range categories {
+		if category.Matches(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func paginate(torrents []interfaces.Torrent, offset int, limit int) []interfaces.Torrent {
+	if offset < 0 || offset >= len(torrents) {
+		return nil
+	}
+	torrents = torrents[offset:]
+	if len(torrents) > limit {
+		torrents = torrents[:limit]
+	}
+	return torrents
+}
+
+func capabilities() caps {
+	supported := capsSearch{Available: "yes", SupportedParams: "q"}
+	c := caps{
+		Server: capsServer{Title: "gotorrent"},
+		Limits: capsLimits{Max: maxLimit, Default: defaultLimit},
+		Searching: capsSearching{
+			Search:      supported,
+			TVSearch:    capsSearch{Available: "yes", SupportedParams: "q,season,ep,imdbid"},
+			MovieSearch: capsSearch{Available: "yes", SupportedParams: "q,imdbid"},
+		},
+	}
+
+	all := interfaces.Categories()
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	for _, category := range all {
+		if category.IsParent() {
+			c.Categories = append(c.Categories, capsCategory{ID: int(category), Name: category.String()})
+			continue
+		}
+		parent := &c.Categories[len(c.Categories)-1]
+		parent.Subcats = append(parent.Subcats, capsCategory{ID: int(category), Name: category.String()})
+	}
+	return c
+}
+
+func toRSS(torrents []interfaces.Torrent) rss {
+	feed := rss{
+		Version: "2.0",
+		Atom:    atomNamespace,
+		Torznab: torznabNamespace,
+		Channel: rssChannel{
+			Title:       "gotorrent",
+			Description: "gotorrent Torznab feed",
+			Link:        "https://github.com/ismaelpadilla/gotorrent",
+		},
+	}
+
+	for _, t := range torrents {
+		item := rssItem{
+			Title: t.Title,
+			GUID:  t.Key(),
+			Link:  t.MagnetLink,
+			Size:  t.Size,
+			Enclosure: rssEnclosure{
+				URL:    t.MagnetLink,
+				Length: t.Size,
+				Type:   "application/x-bittorrent",
+			},
+			Attributes: []torznabAttr{
+				{"seeders", strconv.Itoa(t.Seeders)},
+				{"peers", strconv.Itoa(t.Seeders + t.Leechers)},
+				{"leechers", strconv.Itoa(t.Leechers)},
+				{"infohash", strings.ToLower(t.InfoHash)},
+				{"magneturl", t.MagnetLink},
+				{"size", strconv.Itoa(t.Size)},
+			},
+		}
+		if t.IMDbID != "" {
+			item.Attributes = append(item.Attributes,
+				torznabAttr{"imdbid", t.IMDbID},
+				torznabAttr{"imdb", strings.TrimPrefix(t.IMDbID, "tt")})
+		}
+		if uploaded := t.UploadedTime(); !uploaded.IsZero() {
+			item.PubDate = uploaded.UTC().Format(time.RFC1123Z)
+		}
+
+		category := t.Category
+		if category == interfaces.CategoryUnknown {
+			category = guessCategory(t.Release)
+		}
+		item.Category = []int{int(category)}
+		if !category.IsParent() {
+			item.Category = append(item.Category, int(category.Parent()))
+		}
+		item.Attributes = append(item.Attributes, torznabAttr{"category", strconv.Itoa(int(category))})
+
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed
+}
+
+// guessCategory uses the release name to find a category for torrents whose
+// provider doesn't have one
+func guessCategory(info release.Info) interfaces.Category {
+	switch {
+	case info.Season > 0 || info.Episode > 0:
+		return interfaces.CategoryTV
+	case info.Resolution != "" || info.Source != "":
+		return interfaces.CategoryMovies
+	default:
+		return interfaces.CategoryOther
+	}
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	_, _ = w.Write([]byte(xml.Header))
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+func writeTorznabError(w http.ResponseWriter, code int, description string) {
+	writeXML(w, torznabError{Code: code, Description: description})
+}
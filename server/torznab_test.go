@@ -0,0 +1,159 @@
+package server
+
+import (
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// fakeProvider returns pages of results named after the query, and its
+// latest torrents if it has any
+type fakeProvider struct {
+	pages  [][]string
+	latest []string
+}
+
+func (p fakeProvider) Name() string { return "fake" }
+
+func (p fakeProvider) Search(query string) []interfaces.Torrent {
+	torrents, _ := p.SearchPage(query, "")
+	return torrents
+}
+
+func (p fakeProvider) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	number, _ := strconv.Atoi(page)
+	if number >= len(p.pages) {
+		return nil, ""
+	}
+	next := ""
+	if number+1 < len(p.pages) {
+		next = strconv.Itoa(number + 1)
+	}
+	return torrents(p.pages[number]), next
+}
+
+func (p fakeProvider) NavigateTo(interfaces.Torrent) {}
+
+func (p fakeProvider) FetchTorrentDescription(interfaces.Torrent) string { return "" }
+
+func (p fakeProvider) FetchTorrentFiles(interfaces.Torrent) []interfaces.TorrentFile { return nil }
+
+// latestProvider can list its latest torrents
+type latestProvider struct {
+	fakeProvider
+}
+
+func (p latestProvider) Latest() []interfaces.Torrent {
+	return torrents(p.latest)
+}
+
+func torrents(titles []string) []interfaces.Torrent {
+	var torrents []interfaces.Torrent
+	for _, title := range titles {
+		torrents = append(torrents, interfaces.Torrent{Title: title, InfoHash: title})
+	}
+	return torrents
+}
+
+// torznabTitles sends a Torznab request and returns the titles of the results
+func torznabTitles(t *testing.T, provider interfaces.Client, query string) []string {
+	t.Helper()
+	var titles []string
+	for _, item := range torznabItems(t, provider, query) {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+// torznabItems sends a Torznab request and returns the results
+func torznabItems(t *testing.T, provider interfaces.Client, query string) []rssItem {
+	t.Helper()
+	handler := New(Config{
+		Providers: []interfaces.Client{provider},
+		Logger:    log.New(io.Discard, "", 0),
+	})
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/torznab/api?"+query, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("%s: status %d", query, recorder.Code)
+	}
+
+	var feed rss
+	if err := xml.Unmarshal(recorder.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("%s: %v\n%s", query, err, recorder.Body)
+	}
+	return feed.Channel.Items
+}
+
+func TestTorznabEmptyQuery(t *testing.T) {
+	provider := fakeProvider{pages: [][]string{{"a", "b"}}, latest: []string{"new"}}
+
+	if titles := torznabTitles(t, latestProvider{provider}, "t=search"); len(titles) != 1 || titles[0] != "new" {
+		t.Errorf("results = %q, want the latest torrents", titles)
+	}
+	if titles := torznabTitles(t, provider, "t=search&q="); len(titles) != 0 {
+		t.Errorf("results = %q, want none from a provider that can't list its latest torrents", titles)
+	}
+	if titles := torznabTitles(t, latestProvider{provider}, "t=search&q=ubuntu"); len(titles) != 2 {
+		t.Errorf("results = %q, want the search results", titles)
+	}
+}
+
+func TestTorznabOffset(t *testing.T) {
+	provider := fakeProvider{pages: [][]string{{"a", "b"}, {"c", "d"}, {"e"}}}
+
+	titles := torznabTitles(t, provider, "t=search&q=x&offset=3&limit=2")
+	if len(titles) != 2 || titles[0] != "d" || titles[1] != "e" {
+		t.Errorf("results = %q, want d and e from the later pages", titles)
+	}
+	if titles := torznabTitles(t, provider, "t=search&q=x&offset=10"); len(titles) != 0 {
+		t.Errorf("results = %q, want none past the last page", titles)
+	}
+}
+
+// idProvider returns torrents without info-hash, identified by their ID
+type idProvider struct {
+	fakeProvider
+}
+
+func (p idProvider) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	torrents, next := p.fakeProvider.SearchPage(query, page)
+	for i := range torrents {
+		torrents[i].ID, torrents[i].InfoHash = torrents[i].InfoHash, ""
+	}
+	return torrents, next
+}
+
+func TestTorznabGUIDs(t *testing.T) {
+	items := torznabItems(t, idProvider{fakeProvider{pages: [][]string{{"a", "b"}}}}, "t=search&q=x")
+	if len(items) != 2 || items[0].GUID == items[1].GUID || items[0].GUID == "" {
+		t.Errorf("results = %+v, want a GUID for each torrent", items)
+	}
+}
+
+func TestTorznabLimit(t *testing.T) {
+	var titles []string
+	for i := 0; i < 150; i++ {
+		titles = append(titles, strconv.Itoa(i))
+	}
+	provider := fakeProvider{pages: [][]string{titles}}
+
+	tests := map[string]int{
+		"":            100,
+		"&limit=10":   10,
+		"&limit=500":  100,
+		"&limit=-1":   100,
+		"&limit=many": 100,
+	}
+	for limit, want := range tests {
+		if got := torznabTitles(t, provider, "t=search&q=x"+limit); len(got) != want {
+			t.Errorf("%s: %d results, want %d", limit, len(got), want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package torrentcache
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/xdg"
+)
+
+// .torrent files are bigger than this only for huge multi file torrents
+const maxTorrentSize = 20 << 20
+
+var infoHashRegex = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Cache downloads .torrent files by info-hash and keeps them on disk.
+type Cache struct {
+	dir string
+	web *httpclient.Client
+}
+
+// DefaultDir returns the location of the cache.
+func DefaultDir() string {
+	return filepath.Join(xdg.CacheDir(), "torrents")
+}
+
+// New returns a cache that keeps files in dir and downloads them with web.
+func New(dir string, web *httpclient.Client) *Cache {
+	return &Cache{dir: dir, web: web}
+}
+
+// URL returns the address the .torrent file for an info-hash is downloaded
+// from.
+func URL(infoHash string) string {
+	return fmt.Sprintf("https://itorrents.org/torrent/%s.torrent", strings.ToUpper(infoHash))
+}
+
+// ValidInfoHash returns true if s is a hex encoded info-hash.
+func ValidInfoHash(s string) bool {
+	return infoHashRegex.MatchString(strings.ToLower(s))
+}
+
+// Get returns the .torrent file for an info-hash, downloading it if it's not
+// in the cache.
+func (c *Cache) Get(infoHash string) ([]byte, error) {
+	infoHash = strings.ToLower(infoHash)
+	if !ValidInfoHash(infoHash) {
+		return nil, fmt.Errorf("invalid info-hash %q", infoHash)
+	}
+
+	path := filepath.Join(c.dir, infoHash+".torrent")
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	data, err = c.download(infoHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// failing to cache the file is not a reason to fail the download
+	if err := os.MkdirAll(c.dir, 0o700); err == nil {
+		_ = os.WriteFile(path, data, 0o600)
+	}
+	return data, nil
+}
+
+func (c *Cache) download(infoHash string) ([]byte, error) {
+	data, err := c.web.Get(URL(infoHash))
+	if err != nil {
+		return nil, fmt.Errorf("downloading .torrent file: %w", err)
+	}
+	if len(data) > maxTorrentSize {
+		return nil, errors.New("downloading .torrent file: file too large")
+	}
+	// bencoded dictionaries start with 'd', anything else is an error page
+	if len(data) == 0 || data[0] != 'd' {
+		return nil, errors.New("downloading .torrent file: not a .torrent file")
+	}
+	return data, nil
+}
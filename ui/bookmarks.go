@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ismaelpadilla/gotorrent/bookmarks"
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/ui/keys"
+)
+
+// startBookmark asks for a note for the current torrent, the bookmark is
+// saved once the note is entered
+func (m *Model) startBookmark() tea.Cmd {
+	if m.bookmarks == nil {
+		m.message = "Bookmarks are not available"
+		return nil
+	}
+	m.previousMode = m.mode
+	m.noteInput.SetValue("")
+	cmd := m.noteInput.Focus()
+	m.keys = keys.BookmarkNoteKeys
+	m.mode = BookmarkNote
+
+	return cmd
+}
+
+// finishBookmark saves the current torrent as a bookmark if save is true, and
+// goes back to the mode where the bookmark was started
+func (m *Model) finishBookmark(save bool) {
+	m.noteInput.Blur()
+	m.setMode(m.previousMode)
+	if !save {
+		return
+	}
+
+	b := bookmarks.FromTorrent(*m.getCurrentTorrent(), m.noteInput.Value())
+	if err := m.bookmarks.Add(b); err != nil {
+		m.errorMessage = "Error while saving bookmark: " + err.Error()
+	} else {
+		m.message = "Bookmark saved"
+	}
+}
+
+// enterBookmarks shows the saved bookmarks instead of the search results
+func (m *Model) enterBookmarks() {
+	if m.bookmarks == nil {
+		m.message = "Bookmarks are not available"
+		return
+	}
+	m.listCursor = m.cursorPosition
+	m.cursorPosition = 0
+	m.loadBookmarks()
+	m.listMode = Bookmarks
+	m.setMode(Bookmarks)
+}
+
+// leaveBookmarks goes back to the search results
+func (m *Model) leaveBookmarks() {
+	m.listMode = List
+	m.cursorPosition = m.listCursor
+	m.applyFilterAndSort()
+	m.setMode(List)
+}
+
+func (m *Model) loadBookmarks() {
+	list, err := m.bookmarks.List()
+	if err != nil {
+		m.errorMessage = "Error while reading bookmarks: " + err.Error()
+	}
+	m.bookmarkList = list
+
+	m.torrents = make([]interfaces.Torrent, len(list))
+	for i, b := range list {
+		// the torrent can still be opened even if its provider isn't
+		// available, so errors are ignored
+		client, _ := clients.Get(b.Provider)
+		m.torrents[i] = b.Torrent(client)
+	}
+
+	if m.cursorPosition > len(m.torrents)-1 {
+		m.cursorPosition = len(m.torrents) - 1
+	}
+	if m.cursorPosition < 0 {
+		m.cursorPosition = 0
+	}
+}
+
+func (m *Model) deleteBookmark() {
+	b, ok := m.currentBookmark()
+	if !ok {
+		return
+	}
+	if err := m.bookmarks.Remove(b.Key); err != nil {
+		m.errorMessage = "Error while deleting bookmark: " + err.Error()
+		return
+	}
+	m.loadBookmarks()
+	m.message = "Bookmark deleted"
+}
+
+// currentBookmark returns the bookmark under the cursor, if bookmarks are
+// being shown
+func (m *Model) currentBookmark() (bookmarks.Bookmark, bool) {
+	if m.listMode != Bookmarks || m.cursorPosition >= len(m.bookmarkList) {
+		return bookmarks.Bookmark{}, false
+	}
+	return m.bookmarkList[m.cursorPosition], true
+}
+
+func (m *Model) GetBookmarksTable() string {
+	return m.renderTable(bookmarkColumns, m.torrents)
+}
+
+// setMode changes the mode and the keys shown in the help view
+func (m *Model) setMode(mode Mode) {
+	m.mode = mode
+	m.keys = keysForMode(mode)
+}
+
+func keysForMode(mode Mode) help.KeyMap {
+	switch mode {
+	case ShowDescription:
+		return keys.DescriptionKeys
+	case ShowFiles:
+		return keys.FilesKeys
+	case Search:
+		return keys.SearchKeys
+	case Filter:
+		return keys.FilterKeys
+	case Bookmarks:
+		return keys.BookmarksKeys
+	case BookmarkNote:
+		return keys.BookmarkNoteKeys
+	default:
+		return keys.ListKeys
+	}
+}
@@ -0,0 +1,101 @@
+package ui
+
+import "strings"
+
+const (
+	searchPrompt        = "> "
+	reverseSearchPrompt = "(reverse-i-search) "
+)
+
+// loadHistory reads the queries used in previous searches, so they can be
+// recalled in Search mode
+func (m *Model) loadHistory() {
+	m.historyQueries = nil
+	if m.history != nil {
+		queries, err := m.history.Queries()
+		if err != nil {
+			m.errorMessage = "Error while reading search history: " + err.Error()
+		}
+		m.historyQueries = queries
+	}
+	m.historyIndex = len(m.historyQueries)
+	m.draftQuery = ""
+	m.reverseSearch = false
+	m.searchInput.Prompt = searchPrompt
+}
+
+// previousQuery replaces the search input with the previous query in the
+// history
+func (m *Model) previousQuery() {
+	if m.historyIndex == 0 {
+		return
+	}
+	if m.historyIndex == len(m.historyQueries) {
+		m.draftQuery = m.searchInput.Value()
+	}
+	m.historyIndex--
+	m.searchInput.SetValue(m.historyQueries[m.historyIndex])
+	m.searchInput.CursorEnd()
+}
+
+// nextQuery replaces the search input with the next query in the history,
+// or with whatever was being typed once the end of the history is reached
+func (m *Model) nextQuery() {
+	if m.historyIndex >= len(m.historyQueries) {
+		return
+	}
+	m.historyIndex++
+	if m.historyIndex == len(m.historyQueries) {
+		m.searchInput.SetValue(m.draftQuery)
+	} else {
+		m.searchInput.SetValue(m.historyQueries[m.historyIndex])
+	}
+	m.searchInput.CursorEnd()
+}
+
+// startReverseSearch enters reverse incremental search, or looks for an
+// older match if it was already active
+func (m *Model) startReverseSearch() {
+	if !m.reverseSearch {
+		m.reverseSearch = true
+		m.searchInput.Prompt = reverseSearchPrompt
+		m.updateReverseSearch(len(m.historyQueries) - 1)
+		return
+	}
+	m.updateReverseSearch(m.reverseMatch - 1)
+}
+
+// updateReverseSearch looks for the most recent query that contains the
+// search input, starting at the given position of the history
+func (m *Model) updateReverseSearch(from int) {
+	pattern := strings.ToLower(m.searchInput.Value())
+	for i := from; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(m.historyQueries[i]), pattern) {
+			m.reverseMatch = i
+			return
+		}
+	}
+	// keep the current match when there are no older ones
+	if from < len(m.historyQueries)-1 && m.reverseMatch >= 0 && strings.Contains(strings.ToLower(m.historyQueries[m.reverseMatch]), pattern) {
+		return
+	}
+	m.reverseMatch = -1
+}
+
+// stopReverseSearch leaves reverse incremental search. If accept is true the
+// matched query replaces the search input.
+func (m *Model) stopReverseSearch(accept bool) {
+	if accept && m.reverseMatch >= 0 {
+		m.searchInput.SetValue(m.historyQueries[m.reverseMatch])
+		m.searchInput.CursorEnd()
+	}
+	m.reverseSearch = false
+	m.searchInput.Prompt = searchPrompt
+}
+
+func (m *Model) reverseSearchMatch() string {
+	if m.reverseMatch < 0 {
+		return "no matching query"
+	}
+	return m.historyQueries[m.reverseMatch]
+}
@@ -0,0 +1,168 @@
+package ui
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/ismaelpadilla/gotorrent/history"
+)
+
+// newHistoryModel returns a model in Search mode whose history contains the
+// given queries, oldest first
+func newHistoryModel(t *testing.T, queries ...string) *Model {
+	t.Helper()
+	store := history.New(filepath.Join(t.TempDir(), "history.jsonl"))
+	for _, q := range queries {
+		if err := store.Add(history.Entry{Query: q}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := newTestModel()
+	m.mode = Search
+	m.history = store
+	m.searchInput = textinput.New()
+	m.loadHistory()
+	return m
+}
+
+func TestPreviousAndNextQuery(t *testing.T) {
+	m := newHistoryModel(t, "ubuntu", "debian", "arch")
+	m.searchInput.SetValue("half typed")
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		m.previousQuery()
+		got = append(got, m.searchInput.Value())
+	}
+	// the oldest query stays once it's reached
+	if want := []string{"arch", "debian", "ubuntu", "ubuntu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("previous queries = %q, want %q", got, want)
+	}
+
+	got = nil
+	for i := 0; i < 4; i++ {
+		m.nextQuery()
+		got = append(got, m.searchInput.Value())
+	}
+	// going past the newest query restores what was being typed
+	if want := []string{"debian", "arch", "half typed", "half typed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("next queries = %q, want %q", got, want)
+	}
+}
+
+func TestRecallEmptyHistory(t *testing.T) {
+	m := newHistoryModel(t)
+	m.searchInput.SetValue("ubuntu")
+	m.previousQuery()
+	m.nextQuery()
+	if v := m.searchInput.Value(); v != "ubuntu" {
+		t.Errorf("input = %q, want it unchanged", v)
+	}
+}
+
+func TestReverseSearch(t *testing.T) {
+	m := newHistoryModel(t, "ubuntu 22.04", "debian", "Ubuntu 20.04", "arch")
+
+	m.startReverseSearch()
+	if m.searchInput.Prompt != reverseSearchPrompt {
+		t.Errorf("prompt = %q, want %q", m.searchInput.Prompt, reverseSearchPrompt)
+	}
+	if got := m.reverseSearchMatch(); got != "arch" {
+		t.Errorf("match of an empty pattern = %q, want the newest query", got)
+	}
+
+	// matching is case insensitive and starts from the newest query
+	m.searchInput.SetValue("ubuntu")
+	m.updateReverseSearch(len(m.historyQueries) - 1)
+	if got := m.reverseSearchMatch(); got != "Ubuntu 20.04" {
+		t.Errorf("match = %q, want %q", got, "Ubuntu 20.04")
+	}
+
+	// searching again looks for an older match, and keeps it at the end
+	for i := 0; i < 2; i++ {
+		m.startReverseSearch()
+		if got := m.reverseSearchMatch(); got != "ubuntu 22.04" {
+			t.Errorf("older match = %q, want %q", got, "ubuntu 22.04")
+		}
+	}
+
+	m.stopReverseSearch(true)
+	if m.reverseSearch || m.searchInput.Prompt != searchPrompt {
+		t.Error("reverse search is still active")
+	}
+	if v := m.searchInput.Value(); v != "ubuntu 22.04" {
+		t.Errorf("input = %q, want the accepted match", v)
+	}
+}
+
+func TestReverseSearchNoMatch(t *testing.T) {
+	m := newHistoryModel(t, "ubuntu", "debian")
+	m.startReverseSearch()
+	m.searchInput.SetValue("gentoo")
+	m.updateReverseSearch(len(m.historyQueries) - 1)
+	if got := m.reverseSearchMatch(); got != "no matching query" {
+		t.Errorf("match = %q, want none", got)
+	}
+
+	m.stopReverseSearch(true)
+	if v := m.searchInput.Value(); v != "gentoo" {
+		t.Errorf("input = %q, want it unchanged", v)
+	}
+}
+
+func TestReverseSearchCancel(t *testing.T) {
+	m := newHistoryModel(t, "ubuntu")
+	m.searchInput.SetValue("ubu")
+	m.startReverseSearch()
+	m.stopReverseSearch(false)
+	if v := m.searchInput.Value(); v != "ubu" {
+		t.Errorf("input = %q, want it unchanged", v)
+	}
+}
+
+func TestSearchesAreRecorded(t *testing.T) {
+	m := newHistoryModel(t)
+	for _, q := range []string{"ubuntu", "fail", "debian"} {
+		cmd, ok := m.search(q)
+		if !ok {
+			t.Fatalf("search(%q) failed", q)
+		}
+		m.showResults(cmd().(searchResultsMsg))
+	}
+
+	queries, err := m.history.Queries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// failed searches aren't recorded
+	if want := []string{"ubuntu", "debian"}; !reflect.DeepEqual(queries, want) {
+		t.Errorf("history = %q, want %q", queries, want)
+	}
+}
+
+func TestPrivateMode(t *testing.T) {
+	// --private leaves the history out of the model
+	m := newTestModel()
+	m.searchInput = textinput.New()
+	m.loadHistory()
+	if len(m.historyQueries) != 0 {
+		t.Errorf("recalled queries = %q, want none", m.historyQueries)
+	}
+
+	cmd, ok := m.search("ubuntu")
+	if !ok {
+		t.Fatal("search failed")
+	}
+	m.showResults(cmd().(searchResultsMsg))
+	if m.errorMessage != "" || len(m.torrents) != 1 {
+		t.Errorf("results = %+v, error %q, want the search to work", m.torrents, m.errorMessage)
+	}
+
+	m.previousQuery()
+	m.startReverseSearch()
+	if got := m.reverseSearchMatch(); got != "no matching query" {
+		t.Errorf("match = %q, want none", got)
+	}
+}
@@ -0,0 +1,64 @@
+package keys
+
+import "github.com/charmbracelet/bubbles/key"
+
+type bookmarksKeyMap struct {
+	Up                key.Binding
+	Down              key.Binding
+	Top               key.Binding
+	Bottom            key.Binding
+	Enter             key.Binding
+	DownloadTorrent   key.Binding
+	NavigateToTorrent key.Binding
+	CopyMagnetLink    key.Binding
+	ShowDescription   key.Binding
+	ShowFiles         key.Binding
+	TogglePreview     key.Binding
+	SwitchPreview     key.Binding
+	DeleteBookmark    key.Binding
+	Search            key.Binding
+	GoBack            key.Binding
+	Help              key.Binding
+	Quit              key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k bookmarksKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.GoBack, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k bookmarksKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom, k.Enter, k.NavigateToTorrent},         // first column
+		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowDescription, k.ShowFiles}, // second column
+		{k.TogglePreview, k.SwitchPreview, k.DeleteBookmark, k.Search},        // third column
+		{k.Help, k.GoBack, k.Quit},                                            // fourth column
+	}
+}
+
+var BookmarksKeys = newBookmarksKeys()
+
+func newBookmarksKeys() bookmarksKeyMap {
+	return bookmarksKeyMap{
+		Up:                All.Up,
+		Down:              All.Down,
+		Top:               All.Top,
+		Bottom:            All.Bottom,
+		Enter:             All.GetTorrent,
+		NavigateToTorrent: All.NavigateToTorrent,
+		DownloadTorrent:   All.DownloadTorrent,
+		CopyMagnetLink:    All.CopyMagnetLink,
+		ShowDescription:   All.ShowDescription,
+		ShowFiles:         All.ShowFiles,
+		TogglePreview:     All.TogglePreview,
+		SwitchPreview:     All.SwitchPreview,
+		DeleteBookmark:    All.DeleteBookmark,
+		Search:            All.SearchS,
+		GoBack:            All.GoBackQEsc,
+		Help:              All.Help,
+		Quit:              All.CtrlC,
+	}
+}
@@ -0,0 +1,258 @@
+package keys
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// actions are the names used in the [keys] config section, and the bindings
+// they change
+var actions = map[string][]*key.Binding{
+	"up":               {&All.Up},
+	"down":             {&All.Down},
+	"top":              {&All.Top},
+	"bottom":           {&All.Bottom},
+	"get-torrent":      {&All.GetTorrent},
+	"go-to-torrent":    {&All.NavigateToTorrent},
+	"download-torrent": {&All.DownloadTorrent},
+	"copy-magnet-link": {&All.CopyMagnetLink},
+	"show-description": {&All.ShowDescription},
+	"show-files":       {&All.ShowFiles},
+	"search":           {&All.SearchS},
+	"previous-query":   {&All.PreviousQuery},
+	"next-query":       {&All.NextQuery},
+	"reverse-search":   {&All.ReverseSearch},
+	"bookmark":         {&All.AddBookmark},
+	"show-bookmarks":   {&All.ShowBookmarks},
+	"delete-bookmark":  {&All.DeleteBookmark},
+	"filter":           {&All.Filter},
+	"sort":             {&All.Sort},
+	"reverse-sort":     {&All.ReverseSort},
+	"new-tab":          {&All.NewTab},
+	"close-tab":        {&All.CloseTab},
+	"next-tab":         {&All.NextTab},
+	"previous-tab":     {&All.PreviousTab},
+	"toggle-preview":   {&All.TogglePreview},
+	"switch-preview":   {&All.SwitchPreview},
+	"next-link":        {&All.NextLink},
+	"previous-link":    {&All.PreviousLink},
+	"open-link":        {&All.OpenLink},
+	"help":             {&All.Help},
+	"go-back":          {&All.GoBackQEsc},
+	"quit":             {&All.QuitQEsc},
+	"force-quit":       {&All.CtrlC},
+	"confirm":          {&All.SearchEnter, &All.FilterEnter, &All.SaveBookmark},
+	"cancel":           {&All.GoBackEsc},
+}
+
+// Presets are sets of remapped actions that can be used as a base for the
+// user's keys.
+var Presets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"top":           {"home", "g g"},
+		"next-tab":      {"g t"},
+		"previous-tab":  {"g T"},
+		"bottom":        {"end", "G"},
+		"go-to-torrent": {"w"},
+		"go-back":       {"q", "esc", "h"},
+	},
+	"emacs": {
+		"up":             {"up", "ctrl+p"},
+		"down":           {"down", "ctrl+n"},
+		"top":            {"home", "alt+<"},
+		"bottom":         {"end", "alt+>"},
+		"go-back":        {"q", "esc", "ctrl+g"},
+		"cancel":         {"esc", "ctrl+g"},
+		"previous-query": {"up", "ctrl+p"},
+		"next-query":     {"down", "ctrl+n"},
+	},
+}
+
+// the actions available in each mode, which can't share keys
+var modes = map[string][]string{
+	"list": {"up", "down", "top", "bottom", "get-torrent", "go-to-torrent", "download-torrent",
+		"copy-magnet-link", "show-description", "show-files", "search", "filter", "sort",
+		"reverse-sort", "bookmark", "show-bookmarks", "new-tab", "close-tab", "next-tab", "previous-tab",
+		"toggle-preview", "switch-preview", "help", "quit", "force-quit"},
+	"bookmarks": {"up", "down", "top", "bottom", "get-torrent", "go-to-torrent", "download-torrent",
+		"copy-magnet-link", "show-description", "show-files", "delete-bookmark", "search",
+		"toggle-preview", "switch-preview", "help", "go-back", "force-quit"},
+	"description and files": {"up", "down", "top", "bottom", "get-torrent", "go-to-torrent",
+		"download-torrent", "copy-magnet-link", "show-description", "show-files", "bookmark",
+		"next-link", "previous-link", "open-link", "search", "help", "go-back", "force-quit"},
+	"search":                   {"confirm", "cancel", "previous-query", "next-query", "reverse-search", "force-quit"},
+	"filter and bookmark note": {"confirm", "cancel", "force-quit"},
+}
+
+// modes where text is typed, so printable keys can't be bound
+var textModes = map[string]bool{"search": true, "filter and bookmark note": true}
+
+// modes where a torrent number can be typed
+var numberModes = map[string]bool{"list": true, "bookmarks": true}
+
+// keys used to input a torrent number in lists
+var numberKeys = []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "backspace"}
+
+// Configure applies a preset and then remaps the given actions to new keys.
+// It returns an error if an action or preset doesn't exist, or if keys
+// conflict in any mode, in which case no keys are changed.
+func Configure(preset string, remap map[string][]string) error {
+	if preset == "" {
+		preset = "default"
+	}
+	presetKeys, ok := Presets[preset]
+	if !ok {
+		return fmt.Errorf("unknown keys preset %q", preset)
+	}
+
+	changed := make(map[string][]string, len(presetKeys)+len(remap))
+	for action, keys := range presetKeys {
+		changed[action] = keys
+	}
+	for action, keys := range remap {
+		if _, ok := actions[action]; !ok {
+			return fmt.Errorf("unknown action %q in keys", action)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no keys for action %q", action)
+		}
+		changed[action] = keys
+	}
+
+	if err := checkConflicts(changed); err != nil {
+		return err
+	}
+
+	for action, keys := range changed {
+		for _, binding := range actions[action] {
+			binding.SetKeys(keys...)
+			binding.SetHelp(helpKeys(keys), binding.Help().Desc)
+		}
+	}
+	rebuild()
+	return nil
+}
+
+// checkConflicts looks for keys bound to more than one action of a mode
+func checkConflicts(changed map[string][]string) error {
+	keysOf := func(action string) []string {
+		if keys, ok := changed[action]; ok {
+			return keys
+		}
+		return actions[action][0].Keys()
+	}
+
+	// the first keys of sequences such as "g t" wait for the next key in
+	// every mode where text isn't typed, so they can't be used on their own
+	prefixes := map[string]string{}
+	for mode, modeActions := range modes {
+		if textModes[mode] {
+			continue
+		}
+		for _, action := range modeActions {
+			for _, k := range keysOf(action) {
+				if i := strings.Index(k, " "); i > 0 {
+					prefixes[k[:i]] = action
+				}
+			}
+		}
+	}
+
+	var conflicts []string
+	for mode, modeActions := range modes {
+		bound := map[string]string{}
+		if numberModes[mode] {
+			for _, k := range numberKeys {
+				if other, ok := prefixes[k]; ok {
+					conflicts = append(conflicts, fmt.Sprintf("%q is used for torrent number input and starts the keys of %s in %s mode", k, other, mode))
+				}
+				bound[k] = "torrent number input"
+			}
+		}
+		for _, action := range modeActions {
+			for _, k := range keysOf(action) {
+				if textModes[mode] && utf8.RuneCountInString(k) == 1 {
+					conflicts = append(conflicts, fmt.Sprintf("%q can't be used for %s, it's typed in %s mode", k, action, mode))
+					continue
+				}
+				if textModes[mode] && strings.Contains(k, " ") {
+					conflicts = append(conflicts, fmt.Sprintf("%q can't be used for %s, sequences can't be used in %s mode", k, action, mode))
+					continue
+				}
+				if other, ok := prefixes[k]; ok && !textModes[mode] {
+					conflicts = append(conflicts, fmt.Sprintf("%q is used for %s and starts the keys of %s in %s mode", k, action, other, mode))
+					continue
+				}
+				if other, ok := bound[k]; ok && other != action {
+					conflicts = append(conflicts, fmt.Sprintf("%q is used for %s and %s in %s mode", k, other, action, mode))
+					continue
+				}
+				bound[k] = action
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	sort.Strings(conflicts)
+	return fmt.Errorf("conflicting keys:\n  %s", strings.Join(conflicts, "\n  "))
+}
+
+// helpKeys is how keys are shown in the help view, such as "↑/k"
+func helpKeys(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			shown[i] = "↑"
+		case "down":
+			shown[i] = "↓"
+		case " ":
+			shown[i] = "space"
+		default:
+			// sequences are shown the way they're typed, such as "gt"
+			shown[i] = strings.ReplaceAll(k, " ", "")
+		}
+	}
+	return strings.Join(shown, "/")
+}
+
+// IsPrefix returns true if k is the first key of a sequence such as "g t"
+func IsPrefix(k string) bool {
+	for _, bindings := range actions {
+		for _, binding := range bindings {
+			for _, bound := range binding.Keys() {
+				if strings.HasPrefix(bound, k+" ") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// rebuild updates the key maps of every mode with the bindings in All
+func rebuild() {
+	ListKeys = newListKeys()
+	DescriptionKeys = newDescriptionKeys()
+	FilesKeys = newFilesKeys()
+	SearchKeys = newSearchKeys()
+	FilterKeys = newFilterKeys()
+	BookmarksKeys = newBookmarksKeys()
+	BookmarkNoteKeys = newBookmarkNoteKeys()
+}
+
+// Actions returns the names of the actions that can be remapped.
+func Actions() []string {
+	names := make([]string, 0, len(actions))
+	for name := range actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -5,11 +5,17 @@ import "github.com/charmbracelet/bubbles/key"
 type descriptionKeyMap struct {
 	Up                key.Binding
 	Down              key.Binding
+	Top               key.Binding
+	Bottom            key.Binding
 	Enter             key.Binding
 	NavigateToTorrent key.Binding
 	DownloadTorrent   key.Binding
 	CopyMagnetLink    key.Binding
 	ShowFiles         key.Binding
+	AddBookmark       key.Binding
+	NextLink          key.Binding
+	PreviousLink      key.Binding
+	OpenLink          key.Binding
 	GoBack            key.Binding
 	Search            key.Binding
 	Help              key.Binding
@@ -26,22 +32,33 @@ func (k descriptionKeyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k descriptionKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter, k.NavigateToTorrent},       // first column
-		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowFiles}, // second column
-		{k.Search, k.Help, k.GoBack, k.Quit},               // third column
+		{k.Up, k.Down, k.Top, k.Bottom, k.Enter, k.NavigateToTorrent},     // first column
+		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowFiles, k.AddBookmark}, // second column
+		{k.NextLink, k.PreviousLink, k.OpenLink},                          // third column
+		{k.Search, k.Help, k.GoBack, k.Quit},                              // fourth column
 	}
 }
 
-var DescriptionKeys = descriptionKeyMap{
-	Up:                allKeys.Up,
-	Down:              allKeys.Down,
-	Enter:             allKeys.GetTorrent,
-	NavigateToTorrent: allKeys.NavigateToTorrent,
-	DownloadTorrent:   allKeys.DownloadTorrent,
-	CopyMagnetLink:    allKeys.CopyMagnetLink,
-	ShowFiles:         allKeys.ShowFiles,
-	GoBack:            allKeys.GoBackQEsc,
-	Search:            allKeys.SearchS,
-	Help:              allKeys.Help,
-	Quit:              allKeys.CtrlC,
+var DescriptionKeys = newDescriptionKeys()
+
+func newDescriptionKeys() descriptionKeyMap {
+	return descriptionKeyMap{
+		Up:                All.Up,
+		Down:              All.Down,
+		Top:               All.Top,
+		Bottom:            All.Bottom,
+		Enter:             All.GetTorrent,
+		NavigateToTorrent: All.NavigateToTorrent,
+		DownloadTorrent:   All.DownloadTorrent,
+		CopyMagnetLink:    All.CopyMagnetLink,
+		ShowFiles:         All.ShowFiles,
+		AddBookmark:       All.AddBookmark,
+		NextLink:          All.NextLink,
+		PreviousLink:      All.PreviousLink,
+		OpenLink:          All.OpenLink,
+		GoBack:            All.GoBackQEsc,
+		Search:            All.SearchS,
+		Help:              All.Help,
+		Quit:              All.CtrlC,
+	}
 }
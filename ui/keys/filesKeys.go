@@ -5,11 +5,14 @@ import "github.com/charmbracelet/bubbles/key"
 type filesKeyMap struct {
 	Up                key.Binding
 	Down              key.Binding
+	Top               key.Binding
+	Bottom            key.Binding
 	Enter             key.Binding
 	NavigateToTorrent key.Binding
 	DownloadTorrent   key.Binding
 	CopyMagnetLink    key.Binding
 	ShowDescription   key.Binding
+	AddBookmark       key.Binding
 	GoBack            key.Binding
 	Search            key.Binding
 	Help              key.Binding
@@ -26,22 +29,29 @@ func (k filesKeyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k filesKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter, k.NavigateToTorrent},             // first column
-		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowDescription}, // second column
-		{k.Search, k.Help, k.GoBack, k.Quit},                     // third column
+		{k.Up, k.Down, k.Top, k.Bottom, k.Enter, k.NavigateToTorrent},           // first column
+		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowDescription, k.AddBookmark}, // second column
+		{k.Search, k.Help, k.GoBack, k.Quit},                                    // third column
 	}
 }
 
-var FilesKeys = filesKeyMap{
-	Up:                allKeys.Up,
-	Down:              allKeys.Down,
-	Enter:             allKeys.GetTorrent,
-	NavigateToTorrent: allKeys.NavigateToTorrent,
-	DownloadTorrent:   allKeys.DownloadTorrent,
-	CopyMagnetLink:    allKeys.CopyMagnetLink,
-	ShowDescription:   allKeys.ShowDescription,
-	GoBack:            allKeys.GoBackQEsc,
-	Search:            allKeys.SearchS,
-	Help:              allKeys.Help,
-	Quit:              allKeys.CtrlC,
+var FilesKeys = newFilesKeys()
+
+func newFilesKeys() filesKeyMap {
+	return filesKeyMap{
+		Up:                All.Up,
+		Down:              All.Down,
+		Top:               All.Top,
+		Bottom:            All.Bottom,
+		Enter:             All.GetTorrent,
+		NavigateToTorrent: All.NavigateToTorrent,
+		DownloadTorrent:   All.DownloadTorrent,
+		CopyMagnetLink:    All.CopyMagnetLink,
+		ShowDescription:   All.ShowDescription,
+		AddBookmark:       All.AddBookmark,
+		GoBack:            All.GoBackQEsc,
+		Search:            All.SearchS,
+		Help:              All.Help,
+		Quit:              All.CtrlC,
+	}
 }
@@ -0,0 +1,37 @@
+package keys
+
+import "github.com/charmbracelet/bubbles/key"
+
+type filterKeyMap struct {
+	Enter  key.Binding
+	GoBack key.Binding
+	Help   key.Binding
+	Quit   key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k filterKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Enter, k.GoBack, k.Quit}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k filterKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Enter},  // first column
+		{k.GoBack}, // second column
+		{k.Quit},   // third column
+	}
+}
+
+var FilterKeys = newFilterKeys()
+
+func newFilterKeys() filterKeyMap {
+	return filterKeyMap{
+		Enter:  All.FilterEnter,
+		GoBack: All.GoBackEsc,
+		Help:   All.Help,
+		Quit:   All.CtrlC,
+	}
+}
@@ -2,9 +2,12 @@ package keys
 
 import "github.com/charmbracelet/bubbles/key"
 
-type keys struct {
+// Keys are every key binding, each mode uses some of them
+type Keys struct {
 	Up                key.Binding
 	Down              key.Binding
+	Top               key.Binding
+	Bottom            key.Binding
 	GetTorrent        key.Binding
 	NavigateToTorrent key.Binding
 	DownloadTorrent   key.Binding
@@ -15,13 +18,33 @@ type keys struct {
 	GoBackQEsc        key.Binding
 	SearchS           key.Binding
 	SearchEnter       key.Binding
+	PreviousQuery     key.Binding
+	NextQuery         key.Binding
+	ReverseSearch     key.Binding
+	AddBookmark       key.Binding
+	SaveBookmark      key.Binding
+	ShowBookmarks     key.Binding
+	DeleteBookmark    key.Binding
+	Filter            key.Binding
+	FilterEnter       key.Binding
+	Sort              key.Binding
+	ReverseSort       key.Binding
+	TogglePreview     key.Binding
+	SwitchPreview     key.Binding
+	NextLink          key.Binding
+	NewTab            key.Binding
+	CloseTab          key.Binding
+	NextTab           key.Binding
+	PreviousTab       key.Binding
+	PreviousLink      key.Binding
+	OpenLink          key.Binding
 	Help              key.Binding
 	CtrlC             key.Binding
-	QuitQEscCtrlC     key.Binding
+	QuitQEsc          key.Binding
 }
 
-// key definition used in other files
-var allKeys = keys{
+// All is the key definition used in other files, Configure changes it
+var All = Keys{
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
 		key.WithHelp("↑/k", "move up"),
@@ -30,6 +53,14 @@ var allKeys = keys{
 		key.WithKeys("down", "j"),
 		key.WithHelp("↓/j", "move down"),
 	),
+	Top: key.NewBinding(
+		key.WithKeys("home"),
+		key.WithHelp("home", "go to top"),
+	),
+	Bottom: key.NewBinding(
+		key.WithKeys("end"),
+		key.WithHelp("end", "go to bottom"),
+	),
 	GetTorrent: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "get torrent"),
@@ -70,6 +101,86 @@ var allKeys = keys{
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "search"),
 	),
+	PreviousQuery: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑", "previous query"),
+	),
+	NextQuery: key.NewBinding(
+		key.WithKeys("down"),
+		key.WithHelp("↓", "next query"),
+	),
+	ReverseSearch: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "search history"),
+	),
+	AddBookmark: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "bookmark torrent"),
+	),
+	SaveBookmark: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "save bookmark"),
+	),
+	ShowBookmarks: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "show bookmarks"),
+	),
+	DeleteBookmark: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "delete bookmark"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	),
+	FilterEnter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "apply filter"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "change sort column"),
+	),
+	ReverseSort: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "reverse sort order"),
+	),
+	TogglePreview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "toggle preview"),
+	),
+	SwitchPreview: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "switch preview"),
+	),
+	NewTab: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "search in new tab"),
+	),
+	CloseTab: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "close tab"),
+	),
+	NextTab: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next tab"),
+	),
+	PreviousTab: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "previous tab"),
+	),
+	NextLink: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next link"),
+	),
+	PreviousLink: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "previous link"),
+	),
+	OpenLink: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "open link"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "toggle help"),
@@ -78,8 +189,9 @@ var allKeys = keys{
 		key.WithKeys("ctrl+c"),
 		key.WithHelp("ctrl+c", "quit"),
 	),
-	QuitQEscCtrlC: key.NewBinding(
-		key.WithKeys("q", "esc", "ctrl+c"),
+	// ctrl+c is handled by CtrlC
+	QuitQEsc: key.NewBinding(
+		key.WithKeys("q", "esc"),
 		key.WithHelp("q", "quit"),
 	),
 }
@@ -5,13 +5,26 @@ import "github.com/charmbracelet/bubbles/key"
 type listKeyMap struct {
 	Up                key.Binding
 	Down              key.Binding
+	Top               key.Binding
+	Bottom            key.Binding
 	Enter             key.Binding
 	DownloadTorrent   key.Binding
 	NavigateToTorrent key.Binding
 	CopyMagnetLink    key.Binding
 	ShowDescription   key.Binding
 	ShowFiles         key.Binding
+	TogglePreview     key.Binding
+	SwitchPreview     key.Binding
+	AddBookmark       key.Binding
+	ShowBookmarks     key.Binding
 	Search            key.Binding
+	Filter            key.Binding
+	Sort              key.Binding
+	ReverseSort       key.Binding
+	NewTab            key.Binding
+	CloseTab          key.Binding
+	NextTab           key.Binding
+	PreviousTab       key.Binding
 	Help              key.Binding
 	Quit              key.Binding
 }
@@ -26,22 +39,40 @@ func (k listKeyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k listKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Enter, k.NavigateToTorrent},                          // first column
-		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowDescription, k.ShowFiles}, // second column
-		{k.Search, k.Help, k.Quit},                                            // third column
+		{k.Up, k.Down, k.Top, k.Bottom, k.Enter, k.NavigateToTorrent},                               // first column
+		{k.DownloadTorrent, k.CopyMagnetLink, k.ShowDescription, k.ShowFiles},                       // second column
+		{k.Search, k.Filter, k.Sort, k.ReverseSort, k.NewTab, k.CloseTab, k.NextTab, k.PreviousTab}, // third column
+		{k.TogglePreview, k.SwitchPreview, k.AddBookmark, k.ShowBookmarks, k.Help, k.Quit},          // fourth column
 	}
 }
 
-var ListKeys = listKeyMap{
-	Up:                allKeys.Up,
-	Down:              allKeys.Down,
-	Enter:             allKeys.GetTorrent,
-	NavigateToTorrent: allKeys.NavigateToTorrent,
-	DownloadTorrent:   allKeys.DownloadTorrent,
-	CopyMagnetLink:    allKeys.CopyMagnetLink,
-	ShowDescription:   allKeys.ShowDescription,
-	ShowFiles:         allKeys.ShowFiles,
-	Search:            allKeys.SearchS,
-	Help:              allKeys.Help,
-	Quit:              allKeys.QuitQEscCtrlC,
+var ListKeys = newListKeys()
+
+func newListKeys() listKeyMap {
+	return listKeyMap{
+		Up:                All.Up,
+		Down:              All.Down,
+		Top:               All.Top,
+		Bottom:            All.Bottom,
+		Enter:             All.GetTorrent,
+		NavigateToTorrent: All.NavigateToTorrent,
+		DownloadTorrent:   All.DownloadTorrent,
+		CopyMagnetLink:    All.CopyMagnetLink,
+		ShowDescription:   All.ShowDescription,
+		ShowFiles:         All.ShowFiles,
+		TogglePreview:     All.TogglePreview,
+		SwitchPreview:     All.SwitchPreview,
+		AddBookmark:       All.AddBookmark,
+		ShowBookmarks:     All.ShowBookmarks,
+		Search:            All.SearchS,
+		Filter:            All.Filter,
+		Sort:              All.Sort,
+		ReverseSort:       All.ReverseSort,
+		NewTab:            All.NewTab,
+		CloseTab:          All.CloseTab,
+		NextTab:           All.NextTab,
+		PreviousTab:       All.PreviousTab,
+		Help:              All.Help,
+		Quit:              All.QuitQEsc,
+	}
 }
@@ -3,10 +3,13 @@ package keys
 import "github.com/charmbracelet/bubbles/key"
 
 type searchKeyMap struct {
-	Enter  key.Binding
-	GoBack key.Binding
-	Help   key.Binding
-	Quit   key.Binding
+	Enter         key.Binding
+	PreviousQuery key.Binding
+	NextQuery     key.Binding
+	ReverseSearch key.Binding
+	GoBack        key.Binding
+	Help          key.Binding
+	Quit          key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -19,15 +22,22 @@ func (k searchKeyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k searchKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Enter},  // first column
-		{k.GoBack}, // second column
-		{k.Quit},   // third column
+		{k.Enter, k.ReverseSearch},     // first column
+		{k.PreviousQuery, k.NextQuery}, // second column
+		{k.GoBack, k.Quit},             // third column
 	}
 }
 
-var SearchKeys = searchKeyMap{
-	Enter:  allKeys.SearchEnter,
-	GoBack: allKeys.GoBackEsc,
-	Help:   allKeys.Help,
-	Quit:   allKeys.CtrlC,
+var SearchKeys = newSearchKeys()
+
+func newSearchKeys() searchKeyMap {
+	return searchKeyMap{
+		Enter:         All.SearchEnter,
+		PreviousQuery: All.PreviousQuery,
+		NextQuery:     All.NextQuery,
+		ReverseSearch: All.ReverseSearch,
+		GoBack:        All.GoBackEsc,
+		Help:          All.Help,
+		Quit:          All.CtrlC,
+	}
 }
@@ -0,0 +1,98 @@
+package markup
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// cp437 are the characters of code page 437 from 0x80 to 0xFF, used by NFO
+// files for their ASCII art
+var cp437 = []rune("" +
+	"ÇüéâäàåçêëèïîìÄÅ" +
+	"ÉæÆôöòûùÿÖÜ¢£¥₧ƒ" +
+	"áíóúñÑªº¿⌐¬½¼¡«»" +
+	"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" +
+	"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" +
+	"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" +
+	"αßΓπΣσµτΦΘΩδ∞φε∩" +
+	"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■ ")
+
+// DecodeCP437 decodes text in code page 437.
+func DecodeCP437(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		if c < 0x80 {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteRune(cp437[c-0x80])
+		}
+	}
+	return sb.String()
+}
+
+// decode returns s as valid UTF-8. Text that isn't UTF-8 is assumed to be
+// an NFO in code page 437, and lines of art that were decoded as Latin-1
+// are decoded again.
+func decode(s string) string {
+	if !utf8.ValidString(s) {
+		return DecodeCP437([]byte(s))
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if isMisdecodedArt(line) {
+			lines[i] = recodeLatin1(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isMisdecodedArt returns true if a line is mostly made of the Latin-1
+// characters that have the bytes of code page 437's box drawing and block
+// characters, such as "ÛÛÛ²²±±°°"
+func isMisdecodedArt(line string) bool {
+	art, other := 0, 0
+	for _, r := range line {
+		switch {
+		case r >= 0xB0 && r <= 0xDF:
+			art++
+		case r > 0xFF:
+			// not Latin-1
+			return false
+		case !unicode.IsSpace(r):
+			other++
+		}
+	}
+	return art >= 4 && art*2 >= other
+}
+
+func recodeLatin1(line string) string {
+	var sb strings.Builder
+	for _, r := range line {
+		if r >= 0x80 && r <= 0xFF {
+			sb.WriteRune(cp437[r-0x80])
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// isArt returns true if a line looks like ASCII art, which isn't wrapped
+func isArt(line string) bool {
+	symbols, total := 0, 0
+	for _, r := range line {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		total++
+		switch {
+		case r >= 0x2500 && r <= 0x259F:
+			// box drawing and block characters
+			return true
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			symbols++
+		}
+	}
+	return total >= 10 && symbols*2 > total
+}
@@ -0,0 +1,432 @@
+// Package markup renders torrent descriptions, written in BBCode, HTML or
+// markdown, as styled terminal text.
+package markup
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// Styles are the styles used for links and headings, other text is made
+// bold, italic, underlined or struck through.
+type Styles struct {
+	Link    lipgloss.Style
+	Heading lipgloss.Style
+	// Note is used for link numbers
+	Note lipgloss.Style
+}
+
+// Link is a link or IMDb id found in a description.
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Document is a rendered description.
+type Document struct {
+	Text string
+	// Links are numbered in the text, the first one is [1]
+	Links []Link
+}
+
+var (
+	// a BBCode tag, an HTML tag or an HTML comment
+	tagRegexp  = regexp.MustCompile(`(?s)\[(/?)([a-zA-Z*]+[1-6]?)(?:=([^\]]*))?\]|<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s[^>]*)?)/?>|<!--.*?-->`)
+	hrefRegexp = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	// bare URLs and IMDb ids in text
+	urlRegexp  = regexp.MustCompile(`https?://[^\s<>"'\[\]]+|\btt\d{7,8}\b`)
+	ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	markdownLinkRegexp    = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^)\s]+)\)`)
+	markdownBoldRegexp    = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	markdownHeadingRegexp = regexp.MustCompile(`(?m)^#{1,6}[ \t]+(.+)$`)
+	blankLinesRegexp      = regexp.MustCompile(`\n{3,}`)
+)
+
+// tags are the BBCode and HTML tags that are rendered, other tags are
+// removed if they're HTML and kept as text otherwise, since many titles
+// have words in brackets
+var tags = map[string]bool{
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "s": true, "strike": true, "del": true,
+	"url": true, "a": true, "img": true, "quote": true, "blockquote": true, "code": true, "pre": true,
+	"nfo": true, "list": true, "ul": true, "ol": true, "li": true, "*": true, "br": true, "p": true,
+	"div": true, "hr": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"script": true, "style": true, "color": true, "size": true, "font": true, "center": true,
+	"left": true, "right": true, "align": true, "spoiler": true, "span": true, "table": true,
+	"tr": true, "td": true, "th": true, "tbody": true, "thead": true, "hide": true, "youtube": true,
+}
+
+// Render converts a description to styled text wrapped to width, or not
+// wrapped if width is 0. ASCII art and preformatted text aren't wrapped.
+func Render(description string, width int, styles Styles) Document {
+	r := renderer{styles: styles, seen: map[string]int{}}
+	r.render(markdown(decode(description)))
+
+	var lines []string
+	for _, b := range r.blocks {
+		for _, line := range strings.Split(b.text, "\n") {
+			if b.pre || width <= 0 || isArt(ansiRegexp.ReplaceAllString(line, "")) {
+				lines = append(lines, line)
+				continue
+			}
+			lines = append(lines, wrap.String(wordwrap.String(line, width), width))
+		}
+	}
+	text := blankLinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return Document{
+		Text:  strings.TrimSpace(text),
+		Links: r.links,
+	}
+}
+
+// markdown turns markdown links, bold text and headings into HTML
+func markdown(s string) string {
+	s = markdownLinkRegexp.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = markdownBoldRegexp.ReplaceAllString(s, `<b>$1</b>`)
+	return markdownHeadingRegexp.ReplaceAllString(s, `<h3>$1</h3>`)
+}
+
+// a block of text, preformatted blocks aren't wrapped
+type block struct {
+	text string
+	pre  bool
+}
+
+// an open link, whose text is its URL if url is empty
+type openLink struct {
+	url  string
+	text strings.Builder
+	// image links show "image" instead of their URL
+	image bool
+}
+
+type list struct {
+	ordered bool
+	items   int
+}
+
+type renderer struct {
+	styles Styles
+	blocks []block
+	out    strings.Builder
+	// nesting depth of each kind of tag
+	bold, italic, underline, strike, heading, quote, pre, skip int
+
+	link  *openLink
+	lists []list
+	links []Link
+	// seen are the numbers of the links in the list, by URL
+	seen map[string]int
+}
+
+func (r *renderer) render(s string) {
+	last := 0
+	for _, m := range tagRegexp.FindAllStringSubmatchIndex(s, -1) {
+		closing, name, arg := "", "", ""
+		switch {
+		case m[4] >= 0:
+			closing, name = s[m[2]:m[3]], strings.ToLower(s[m[4]:m[5]])
+			if m[6] >= 0 {
+				arg = strings.Trim(s[m[6]:m[7]], `"'`)
+			}
+		case m[10] >= 0:
+			closing, name = s[m[8]:m[9]], strings.ToLower(s[m[10]:m[11]])
+			arg = attribute(s[m[12]:m[13]])
+		}
+		// HTML comments have no name and are dropped
+		if name != "" && !tags[name] {
+			if s[m[0]] == '<' {
+				r.text(s[last:m[0]])
+				last = m[1]
+			}
+			continue
+		}
+		r.text(s[last:m[0]])
+		last = m[1]
+		if name == "" {
+			continue
+		}
+		if closing != "" {
+			r.close(name)
+		} else {
+			r.open(name, arg)
+		}
+	}
+	r.text(s[last:])
+	r.endBlock()
+}
+
+// attribute returns the link of an HTML tag, from its href or src
+func attribute(attrs string) string {
+	m := hrefRegexp.FindStringSubmatch(attrs)
+	if m == nil {
+		return ""
+	}
+	return m[1] + m[2] + m[3]
+}
+
+func (r *renderer) open(name string, arg string) {
+	switch name {
+	case "b", "strong":
+		r.bold++
+	case "i", "em":
+		r.italic++
+	case "u":
+		r.underline++
+	case "s", "strike", "del":
+		r.strike++
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.newLine()
+		r.heading++
+	case "url", "a":
+		r.link = &openLink{url: html.UnescapeString(arg)}
+	case "img":
+		if arg != "" {
+			// HTML images have no content
+			r.write(r.linkStyle().Render("image") + r.linkNumber("image", html.UnescapeString(arg)))
+			return
+		}
+		r.link = &openLink{image: true}
+	case "quote", "blockquote":
+		r.newLine()
+		r.quote++
+		r.setPrefix()
+	case "code", "pre", "nfo":
+		r.endBlock()
+		r.pre++
+	case "list", "ul", "ol":
+		r.newLine()
+		r.lists = append(r.lists, list{ordered: name == "ol" || (name == "list" && arg != "")})
+	case "*", "li":
+		r.newLine()
+		bullet := "• "
+		if n := len(r.lists); n > 0 {
+			r.lists[n-1].items++
+			if r.lists[n-1].ordered {
+				bullet = strconv.Itoa(r.lists[n-1].items) + ". "
+			}
+			bullet = strings.Repeat("  ", n-1) + bullet
+		}
+		r.write(bullet)
+	case "br":
+		r.write("\n" + r.quotePrefix())
+	case "p", "div", "tr", "center", "table":
+		r.newLine()
+	case "td", "th":
+		r.write(" ")
+	case "hr":
+		r.newLine()
+		r.write(strings.Repeat("─", 20) + "\n")
+	case "script", "style":
+		r.skip++
+	}
+}
+
+func (r *renderer) close(name string) {
+	switch name {
+	case "b", "strong":
+		r.bold = decrease(r.bold)
+	case "i", "em":
+		r.italic = decrease(r.italic)
+	case "u":
+		r.underline = decrease(r.underline)
+	case "s", "strike", "del":
+		r.strike = decrease(r.strike)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		r.heading = decrease(r.heading)
+		r.newLine()
+	case "url", "a", "img":
+		r.closeLink()
+	case "quote", "blockquote":
+		r.quote = decrease(r.quote)
+		r.newLine()
+		r.setPrefix()
+	case "code", "pre", "nfo":
+		r.endBlock()
+		r.pre = decrease(r.pre)
+	case "list", "ul", "ol":
+		if len(r.lists) > 0 {
+			r.lists = r.lists[:len(r.lists)-1]
+		}
+		r.newLine()
+	case "p", "div", "table":
+		r.newLine()
+	case "script", "style":
+		r.skip = decrease(r.skip)
+	}
+}
+
+func decrease(depth int) int {
+	if depth > 0 {
+		return depth - 1
+	}
+	return 0
+}
+
+func (r *renderer) closeLink() {
+	l := r.link
+	if l == nil {
+		return
+	}
+	r.link = nil
+	text := l.text.String()
+	url := l.url
+	if url == "" {
+		url = strings.TrimSpace(text)
+		if l.image {
+			text = "image"
+		}
+		r.write(r.linkStyle().Render(text))
+	}
+	if url == "" {
+		return
+	}
+	r.write(r.linkNumber(strings.TrimSpace(text), url))
+}
+
+// text writes text between tags
+func (r *renderer) text(s string) {
+	if s == "" || r.skip > 0 {
+		return
+	}
+	s = html.UnescapeString(s)
+	if r.link != nil {
+		if r.link.url == "" {
+			// the URL is the text, it's written when the link is closed
+			r.link.text.WriteString(s)
+			return
+		}
+		r.link.text.WriteString(s)
+		r.write(r.styled(s, r.linkStyle()))
+		return
+	}
+
+	// bare URLs and IMDb ids become links
+	last := 0
+	for _, m := range urlRegexp.FindAllStringIndex(s, -1) {
+		r.write(r.styled(s[last:m[0]], r.style()))
+		found := s[m[0]:m[1]]
+		url := found
+		if strings.HasPrefix(found, "tt") {
+			url = "https://www.imdb.com/title/" + found + "/"
+		}
+		if r.pre > 0 {
+			// preformatted text is left as it is
+			r.write(found)
+			r.addLink(found, url)
+		} else {
+			r.write(r.styled(found, r.linkStyle()) + r.linkNumber(found, url))
+		}
+		last = m[1]
+	}
+	r.write(r.styled(s[last:], r.style()))
+}
+
+// styled renders each line of s, so lines aren't padded to the same width
+func (r *renderer) styled(s string, style lipgloss.Style) string {
+	if s == "" {
+		return ""
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = style.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n"+r.quotePrefix())
+}
+
+func (r *renderer) style() lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if r.bold > 0 {
+		style = style.Bold(true)
+	}
+	if r.italic > 0 || r.quote > 0 {
+		style = style.Italic(true)
+	}
+	if r.underline > 0 {
+		style = style.Underline(true)
+	}
+	if r.strike > 0 {
+		style = style.Strikethrough(true)
+	}
+	if r.heading > 0 {
+		style = style.Inherit(r.styles.Heading)
+	}
+	return style
+}
+
+func (r *renderer) linkStyle() lipgloss.Style {
+	return r.style().Inherit(r.styles.Link)
+}
+
+// linkNumber adds a link to the list and returns its number, such as " [2]",
+// or nothing if it's not a web link
+func (r *renderer) linkNumber(text string, url string) string {
+	n := r.addLink(text, url)
+	if n == 0 {
+		return ""
+	}
+	return r.styles.Note.Render(" [" + strconv.Itoa(n) + "]")
+}
+
+// addLink adds a link to the list unless it's already there, and returns its
+// number. Links other than web links, such as file: or javascript: ones, are
+// left out and 0 is returned.
+func (r *renderer) addLink(text string, url string) int {
+	if !httpclient.WebLink(url) {
+		return 0
+	}
+	if n, ok := r.seen[url]; ok {
+		return n
+	}
+	if strings.HasPrefix(text, "tt") && strings.Contains(url, "imdb.com") {
+		text = "IMDb " + text
+	}
+	r.links = append(r.links, Link{Text: text, URL: url})
+	r.seen[url] = len(r.links)
+	return len(r.links)
+}
+
+func (r *renderer) quotePrefix() string {
+	return strings.Repeat("│ ", r.quote)
+}
+
+func (r *renderer) write(s string) {
+	r.out.WriteString(s)
+}
+
+// newLine starts a new line, unless the current one is empty
+func (r *renderer) newLine() {
+	s := r.out.String()
+	if s != "" && !strings.HasSuffix(strings.TrimRight(s, "│ "), "\n") {
+		r.write("\n" + r.quotePrefix())
+	}
+}
+
+// setPrefix replaces the quote prefix of the current line, if nothing was
+// written in it yet, when a quote is opened or closed
+func (r *renderer) setPrefix() {
+	s := r.out.String()
+	start := strings.LastIndex(s, "\n") + 1
+	if strings.Trim(s[start:], "│ ") != "" {
+		return
+	}
+	r.out.Reset()
+	r.write(s[:start] + r.quotePrefix())
+}
+
+// endBlock starts a new block, preformatted if a code tag is open
+func (r *renderer) endBlock() {
+	if r.out.Len() > 0 {
+		r.blocks = append(r.blocks, block{text: r.out.String(), pre: r.pre > 0})
+	}
+	r.out.Reset()
+}
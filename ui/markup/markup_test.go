@@ -0,0 +1,161 @@
+package markup
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// render renders a description without wrapping, and removes the styles
+func render(description string) Document {
+	d := Render(description, 0, Styles{})
+	d.Text = ansiRegexp.ReplaceAllString(d.Text, "")
+	return d
+}
+
+func TestRenderTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{"plain text", "Just a description", "Just a description"},
+		{"nested tags", "[b]bold [i]both[/i][/b] <em>em <strong>strong</strong></em>", "bold both em strong"},
+		{"unclosed tags", "[b]bold [i]italic <u>underlined", "bold italic underlined"},
+		{"stray closing tags", "text[/b][/quote]</div> more", "text\n more"},
+		{"unknown bbcode is kept", "Title [2022] [x265]", "Title [2022] [x265]"},
+		{"unknown html is dropped", "<marquee>moving</marquee> <blink>text</blink>", "moving text"},
+		{"comments", "before<!-- hidden\ncomment -->after", "beforeafter"},
+		{"script and style", "<style>p {}</style>shown<script>alert(1)</script>", "shown"},
+		{"entities", "Fast &amp; Furious &lt;3", "Fast & Furious <3"},
+		{"line breaks", "first<br>second<p>third</p>fourth", "first\nsecond\nthird\nfourth"},
+		{"quotes", "[quote]quoted [quote]twice[/quote]back[/quote]after", "│ quoted \n│ │ twice\n│ back\nafter"},
+		{"lists", "[list][*]one[*]two[/list]<ol><li>first<li>second</ol>", "• one\n• two\n1. first\n2. second"},
+		{"nested lists", "<ul><li>one<ul><li>inner</li></ul></li></ul>", "• one\n  • inner"},
+		{"markdown", "# Title\n**bold** and [a link](https://example.com)", "Title\n\nbold and a link [1]"},
+	}
+	for _, tt := range tests {
+		if got := render(tt.description).Text; got != tt.want {
+			t.Errorf("%s: Render(%q) = %q, want %q", tt.name, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestRenderNestedStyles(t *testing.T) {
+	got := Render("[b]bold [i]both[/i] bold[/b] plain", 0, Styles{}).Text
+	bold := lipgloss.NewStyle().Bold(true)
+	want := bold.Render("bold ") + bold.Copy().Italic(true).Render("both") + bold.Render(" bold") + " plain"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderLinks(t *testing.T) {
+	description := `[url=https://example.com/a]first[/url] <a href="https://example.com/b">second</a>
+[url]https://example.com/c[/url] https://example.com/a again
+[img]https://example.com/poster.jpg[/img] <img src='https://example.com/d.png'>
+IMDb: tt0903747 and https://www.imdb.com/title/tt0903747/`
+
+	got := render(description)
+	wantText := "first [1] second [2]\nhttps://example.com/c [3] https://example.com/a [1] again\n" +
+		"image [4] image [5]\nIMDb: tt0903747 [6] and https://www.imdb.com/title/tt0903747/ [6]"
+	if got.Text != wantText {
+		t.Errorf("text = %q, want %q", got.Text, wantText)
+	}
+	// links are numbered in order, and repeated ones keep their number
+	wantLinks := []Link{
+		{"first", "https://example.com/a"},
+		{"second", "https://example.com/b"},
+		{"https://example.com/c", "https://example.com/c"},
+		{"image", "https://example.com/poster.jpg"},
+		{"image", "https://example.com/d.png"},
+		{"IMDb tt0903747", "https://www.imdb.com/title/tt0903747/"},
+	}
+	if !reflect.DeepEqual(got.Links, wantLinks) {
+		t.Errorf("links = %+v, want %+v", got.Links, wantLinks)
+	}
+}
+
+func TestRenderDropsOtherLinks(t *testing.T) {
+	description := `[url=javascript:alert(1)]click[/url] <a href="file:///etc/passwd">file</a>
+<a href="magnet:?xt=urn:btih:abc">magnet</a> [url=//example.com]relative[/url] [url=https://example.com]ok[/url]`
+
+	got := render(description)
+	if want := "click file\nmagnet relative ok [1]"; got.Text != want {
+		t.Errorf("text = %q, want %q", got.Text, want)
+	}
+	if want := []Link{{"ok", "https://example.com"}}; !reflect.DeepEqual(got.Links, want) {
+		t.Errorf("links = %+v, want %+v", got.Links, want)
+	}
+}
+
+func TestRenderPreformatted(t *testing.T) {
+	description := "[code]a   b\n  https://example.com/x[/code]" + strings.Repeat(" word", 20)
+	got := Render(description, 20, Styles{})
+	lines := strings.Split(got.Text, "\n")
+	if lines[0] != "a   b" || lines[1] != "  https://example.com/x" {
+		t.Errorf("preformatted text = %q, want it unchanged", lines[:2])
+	}
+	for _, line := range lines[2:] {
+		if len(line) > 20 {
+			t.Errorf("line %q is wider than 20", line)
+		}
+	}
+	if len(got.Links) != 1 {
+		t.Errorf("links = %+v, want the preformatted one", got.Links)
+	}
+}
+
+func TestRenderArtIsNotWrapped(t *testing.T) {
+	art := "░▒▓█ THE RELEASE GROUP PRESENTS █▓▒░ ░▒▓█████████████████▓▒░"
+	got := Render(art+"\n"+strings.Repeat("word ", 20), 20, Styles{})
+	if lines := strings.Split(got.Text, "\n"); lines[0] != art || len(lines) < 3 {
+		t.Errorf("Render() = %q, want the art on a single line and the text wrapped", got.Text)
+	}
+}
+
+func TestDecodeCP437(t *testing.T) {
+	if got := DecodeCP437([]byte{'A', 0xB0, 0xB1, 0xB2, 0xDB, 0xC9, 0xCD, 0xBB, 0x82}); got != "A░▒▓█╔═╗é" {
+		t.Errorf("DecodeCP437() = %q", got)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"utf-8", "café ░▒▓ naïve", "café ░▒▓ naïve"},
+		// invalid UTF-8 is an NFO
+		{"cp437", string([]byte{0xDB, 0xDB, ' ', 'h', 'i'}), "██ hi"},
+		// art decoded as Latin-1 is decoded again, text isn't
+		{"latin-1 art", "ÛÛÛ²²±±°° NFO\nCafé Ünïcödé", "███▓▓▒▒░░ NFO\nCafé Ünïcödé"},
+		{"too little art", "Û text with few symbols", "Û text with few symbols"},
+	}
+	for _, tt := range tests {
+		if got := decode(tt.in); got != tt.want {
+			t.Errorf("%s: decode(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsArt(t *testing.T) {
+	tests := map[string]bool{
+		"╔════╗":                             true,
+		"  ██  ":                             true,
+		"*-=-*-=-*-=-*-=-*":                  true,
+		"  ___  /  _ \\ | | | |":             true,
+		"Just a sentence about the release.": false,
+		"1080p x265 10bit":                   false,
+		"-=-":                                false,
+		"Video: 1920x1080 (16:9), 23.976 fps, x264": false,
+	}
+	for line, want := range tests {
+		if got := isArt(line); got != want {
+			t.Errorf("isArt(%q) = %t, want %t", line, got, want)
+		}
+	}
+}
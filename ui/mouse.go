@@ -0,0 +1,290 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/ui/keys"
+)
+
+const (
+	// doubleClickTime is the longest time between the clicks of a double
+	// click
+	doubleClickTime = 500 * time.Millisecond
+	// wheelLines is how many lines the mouse wheel scrolls
+	wheelLines = 3
+)
+
+// headerSortFields are the fields the results are sorted by when a column's
+// header is clicked
+var headerSortFields = map[string]sortField{
+	"Title":    sortTitle,
+	"Size":     sortSize,
+	"S":        sortSeeders,
+	"L":        sortLeechers,
+	"Uploaded": sortUploaded,
+	"Res":      sortResolution,
+	"Codec":    sortCodec,
+	"S/E":      sortSeasonEpisode,
+}
+
+// handleMouse scrolls the viewport under the pointer with the wheel, and
+// handles clicks on the results, their headers and the help view
+func (m *Model) handleMouse(msg tea.MouseMsg) (bool, tea.Cmd) {
+	if !m.ready {
+		return false, nil
+	}
+	if msg.Type == tea.MouseLeft {
+		if binding, ok := m.helpBindingAt(msg.X, msg.Y); ok {
+			return m.pressBinding(binding)
+		}
+		// the tab bar is the first line
+		if msg.Y == 0 && m.mode == List && len(m.tabs) > 1 {
+			if i, ok := m.tabAt(msg.X); ok && i != m.activeTab {
+				m.saveTab()
+				m.loadTab(i)
+			}
+			return false, nil
+		}
+	}
+
+	top := m.viewport.YPosition
+	inList := msg.Y >= top && msg.Y < top+m.viewport.Height && msg.X < m.viewport.Width
+	inPreview := false
+	if m.showPreview() {
+		if m.previewSideBySide {
+			inPreview = msg.Y >= top && msg.Y < top+m.previewViewport.Height && msg.X > m.viewport.Width
+		} else {
+			previewTop := top + m.viewport.Height + 1
+			inPreview = msg.Y >= previewTop && msg.Y < previewTop+m.previewViewport.Height
+		}
+	}
+
+	switch msg.Type {
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		up := msg.Type == tea.MouseWheelUp
+		switch {
+		case inPreview:
+			scroll(&m.previewViewport, up)
+		case m.mode == List || m.mode == Bookmarks:
+			return false, m.moveCursor(up)
+		default:
+			scroll(&m.viewport, up)
+		}
+
+	case tea.MouseLeft:
+		if inList && (m.mode == List || m.mode == Bookmarks) {
+			return m.clickList(msg.X, msg.Y-top+m.viewport.YOffset)
+		}
+	}
+	return false, nil
+}
+
+func scroll(v *viewport.Model, up bool) {
+	if up {
+		v.LineUp(wheelLines)
+	} else {
+		v.LineDown(wheelLines)
+	}
+}
+
+// moveCursor moves the cursor with the mouse wheel
+func (m *Model) moveCursor(up bool) tea.Cmd {
+	m.input = ""
+	if up {
+		m.cursorPosition -= wheelLines
+		if m.cursorPosition < 0 {
+			m.cursorPosition = 0
+		}
+		return nil
+	}
+	m.cursorPosition += wheelLines
+	if m.cursorPosition > len(m.torrents)-1 {
+		m.cursorPosition = len(m.torrents) - 1
+	}
+	if m.mode == List {
+		return m.loadMoreAtEnd()
+	}
+	return nil
+}
+
+// clickList handles a click on a line of the results or bookmarks table.
+// Clicking the header sorts the results, clicking a torrent moves the cursor
+// to it and clicking it twice gets it.
+func (m *Model) clickList(x int, line int) (bool, tea.Cmd) {
+	if line == 0 {
+		if m.mode == List {
+			m.sortByColumnAt(x)
+		}
+		return false, nil
+	}
+
+	i := line - 1
+	if i >= len(m.torrents) {
+		return false, nil
+	}
+	doubleClick := i == m.lastClickRow && time.Since(m.lastClick) < doubleClickTime
+	m.lastClick = time.Now()
+	m.lastClickRow = i
+	m.input = ""
+	m.cursorPosition = i
+	if !doubleClick {
+		return false, nil
+	}
+	// a third click starts a new double click
+	m.lastClick = time.Time{}
+	return m.pressBinding(keys.All.GetTorrent)
+}
+
+// sortByColumnAt sorts the results by the column at x, or reverses the order
+// if they're already sorted by it
+func (m *Model) sortByColumnAt(x int) {
+	widths := columnWidths(m.columns, m.viewport.Width)
+	// the cursor is in the first column
+	start := 1
+	for i, c := range m.columns {
+		// columns are preceded by a space
+		start++
+		if x >= start && x < start+widths[i] {
+			field, ok := headerSortFields[c.header]
+			if !ok {
+				return
+			}
+			if m.sortField == field {
+				m.sortDescending = !m.sortDescending
+			} else {
+				m.sortField = field
+			}
+			m.applyFilterAndSort()
+			return
+		}
+		start += widths[i]
+	}
+}
+
+// helpBindingAt returns the key binding of the help view entry at x and y
+func (m *Model) helpBindingAt(x int, y int) (key.Binding, bool) {
+	helpView := m.help.View(m.keys)
+	helpTop := lipgloss.Height(m.View()) - lipgloss.Height(helpView)
+	if y < helpTop {
+		return key.Binding{}, false
+	}
+	row := y - helpTop
+
+	if !m.help.ShowAll {
+		if row != 0 {
+			return key.Binding{}, false
+		}
+		separator := lipgloss.Width(m.help.ShortSeparator)
+		start := 0
+		for _, binding := range m.keys.ShortHelp() {
+			if !binding.Enabled() {
+				continue
+			}
+			if start > 0 {
+				start += separator
+			}
+			width := lipgloss.Width(binding.Help().Key) + 1 + lipgloss.Width(binding.Help().Desc)
+			if x >= start && x < start+width {
+				return binding, true
+			}
+			start += width
+		}
+		return key.Binding{}, false
+	}
+
+	separator := lipgloss.Width(m.help.FullSeparator)
+	start := 0
+	for _, group := range m.keys.FullHelp() {
+		var enabled []key.Binding
+		keysWidth, descriptionsWidth := 0, 0
+		for _, binding := range group {
+			if !binding.Enabled() {
+				continue
+			}
+			enabled = append(enabled, binding)
+			keysWidth = max(keysWidth, lipgloss.Width(binding.Help().Key))
+			descriptionsWidth = max(descriptionsWidth, lipgloss.Width(binding.Help().Desc))
+		}
+		if len(enabled) == 0 {
+			continue
+		}
+		width := keysWidth + 1 + descriptionsWidth
+		if x >= start && x < start+width {
+			if row < len(enabled) {
+				return enabled[row], true
+			}
+			return key.Binding{}, false
+		}
+		start += width + separator
+	}
+	return key.Binding{}, false
+}
+
+// pressBinding handles a click on a key binding as if its first key was
+// pressed. The keys of a sequence such as "g t" are pressed one after the
+// other.
+func (m *Model) pressBinding(binding key.Binding) (bool, tea.Cmd) {
+	if len(binding.Keys()) == 0 {
+		return false, nil
+	}
+	names := strings.Fields(binding.Keys()[0])
+	if len(names) == 0 {
+		// the space key
+		names = []string{binding.Keys()[0]}
+	}
+	msgs := make([]tea.KeyMsg, len(names))
+	for i, name := range names {
+		msg, ok := keyMsg(name)
+		if !ok {
+			return false, nil
+		}
+		msgs[i] = msg
+	}
+
+	// a sequence typed before the click is left unfinished
+	m.pendingKey = ""
+	var cmds []tea.Cmd
+	for _, msg := range msgs {
+		quit, cmd := m.handleKeyPress(msg)
+		if quit {
+			return true, cmd
+		}
+		cmds = append(cmds, cmd)
+	}
+	return false, tea.Batch(cmds...)
+}
+
+// keyMsg returns the message sent when a key is pressed, from its name such
+// as "enter" or "alt+b"
+func keyMsg(name string) (tea.KeyMsg, bool) {
+	k := tea.Key{}
+	if strings.HasPrefix(name, "alt+") && len(name) > len("alt+") {
+		k.Alt = true
+		name = strings.TrimPrefix(name, "alt+")
+	}
+	if len([]rune(name)) == 1 {
+		k.Type = tea.KeyRunes
+		k.Runes = []rune(name)
+		return tea.KeyMsg(k), true
+	}
+	// special keys have negative types and control keys go up to 127
+	for t := tea.KeyF20; t <= tea.KeyCtrlQuestionMark; t++ {
+		if (tea.Key{Type: t}).String() == name {
+			k.Type = t
+			return tea.KeyMsg(k), true
+		}
+	}
+	return tea.KeyMsg{}, false
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
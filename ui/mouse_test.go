@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/mattn/go-runewidth"
+)
+
+// newMouseModel returns a model showing the given results in a 100x30
+// terminal
+func newMouseModel(t *testing.T, titles ...string) Model {
+	t.Helper()
+	m := InitialModel("", Config{Client: fakeClient{}})
+	m.setMode(List)
+	for i, title := range titles {
+		m.results = append(m.results, interfaces.Torrent{Title: title, InfoHash: title, Seeders: i * 10, Size: (len(titles) - i) * 1024})
+	}
+	m.applyFilterAndSort()
+	return update(t, m, tea.WindowSizeMsg{Width: 100, Height: 30})
+}
+
+func update(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	model, _ := m.Update(msg)
+	return model.(Model)
+}
+
+func click(t *testing.T, m Model, x int, y int) Model {
+	t.Helper()
+	return update(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: x, Y: y})
+}
+
+// position returns the position of the first s in the view
+func position(t *testing.T, m Model, s string) (int, int) {
+	t.Helper()
+	for y, line := range strings.Split(m.View(), "\n") {
+		if i := strings.Index(line, s); i >= 0 {
+			return runewidth.StringWidth(line[:i]), y
+		}
+	}
+	t.Fatalf("%q isn't in the view:\n%s", s, m.View())
+	return 0, 0
+}
+
+func shownTitles(m Model) []string {
+	var titles []string
+	for _, torrent := range m.torrents {
+		titles = append(titles, torrent.Title)
+	}
+	return titles
+}
+
+func TestClickHeader(t *testing.T) {
+	m := newMouseModel(t, "b", "c", "a")
+	x, y := position(t, m, "Title")
+
+	m = click(t, m, x, y)
+	if want := []string{"a", "b", "c"}; m.sortField != sortTitle || !reflect.DeepEqual(shownTitles(m), want) {
+		t.Errorf("sorted by %v = %q, want titles %q", m.sortField, shownTitles(m), want)
+	}
+	// clicking it again reverses the order
+	m = click(t, m, x, y)
+	if want := []string{"c", "b", "a"}; !m.sortDescending || !reflect.DeepEqual(shownTitles(m), want) {
+		t.Errorf("sorted = %q, want %q", shownTitles(m), want)
+	}
+
+	// another column sorts by it
+	x, y = position(t, m, "Size")
+	m = click(t, m, x+1, y)
+	if m.sortField != sortSize {
+		t.Errorf("sorted by %v, want the size", m.sortField)
+	}
+
+	// the cursor's column isn't sorted by
+	m = click(t, m, 0, y)
+	if m.sortField != sortSize {
+		t.Errorf("sorted by %v after clicking the cursor, want the size", m.sortField)
+	}
+}
+
+func TestClickRow(t *testing.T) {
+	m := newMouseModel(t, "first", "second", "third")
+
+	x, y := position(t, m, "third")
+	m = click(t, m, x, y)
+	if m.cursorPosition != 2 {
+		t.Errorf("cursor = %d after clicking the third torrent, want 2", m.cursorPosition)
+	}
+
+	// clicking other torrents, or the same one slowly, isn't a double click
+	x, y = position(t, m, "second")
+	m = click(t, m, x, y)
+	m.lastClick = time.Now().Add(-time.Second)
+	if quit, _ := m.handleMouse(tea.MouseMsg{Type: tea.MouseLeft, X: x, Y: y}); quit {
+		t.Error("a slow click got the torrent")
+	}
+	if m.cursorPosition != 1 {
+		t.Errorf("cursor = %d after clicking the second torrent, want 1", m.cursorPosition)
+	}
+
+	// lines after the results do nothing
+	m = click(t, m, x, y+5)
+	if m.cursorPosition != 1 {
+		t.Errorf("cursor = %d after clicking an empty line, want 1", m.cursorPosition)
+	}
+}
+
+func TestClickHelp(t *testing.T) {
+	m := newMouseModel(t, "ubuntu")
+	helpTop := lipgloss.Height(m.View()) - 1
+
+	// the short help's first entry shows the full help
+	m = click(t, m, 0, helpTop)
+	if !m.help.ShowAll {
+		t.Fatal("clicking the help entry didn't show the full help")
+	}
+	x, y := position(t, m, "sort")
+	m = click(t, m, x, y)
+	if m.sortField == sortNone {
+		t.Error("clicking the sort entry didn't sort the results")
+	}
+}
+
+func TestClickHelpSequence(t *testing.T) {
+	remapKeys(t, map[string][]string{"next-tab": {"g t"}, "go-to-torrent": {"w"}})
+	m := newMouseModel(t, "ubuntu")
+	m.tabs = append(m.tabs, tab{id: 1, client: fakeClient{}, query: "debian"})
+	m = update(t, m, keyMsgOf(t, "?"))
+
+	// the keys of the sequence are pressed one after the other
+	x, y := position(t, m, "gt next tab")
+	m = click(t, m, x, y)
+	if m.activeTab != 1 || m.pendingKey != "" {
+		t.Errorf("active tab = %d, pending key %q, want the next tab", m.activeTab, m.pendingKey)
+	}
+
+	// a sequence typed before the click is left unfinished
+	m = update(t, m, keyMsgOf(t, "g"))
+	m = click(t, m, x, y)
+	if m.activeTab != 0 || m.pendingKey != "" {
+		t.Errorf("active tab = %d, pending key %q, want the first tab", m.activeTab, m.pendingKey)
+	}
+}
+
+func keyMsgOf(t *testing.T, name string) tea.KeyMsg {
+	t.Helper()
+	msg, ok := keyMsg(name)
+	if !ok {
+		t.Fatalf("unknown key %q", name)
+	}
+	return msg
+}
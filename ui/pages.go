@@ -0,0 +1,71 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/query"
+)
+
+// loadMoreAtEnd fetches the next page of results when the cursor is on the
+// last torrent and there are more results
+func (m *Model) loadMoreAtEnd() tea.Cmd {
+	if m.nextPage == "" || m.loadingMore || m.cursorPosition < len(m.torrents)-1 {
+		return nil
+	}
+	m.loadingMore = true
+	return cmdLoadMore(m.tabs[m.activeTab].id, m.client, m.query, m.nextPage)
+}
+
+func cmdLoadMore(tab int, client interfaces.Client, text string, page string) tea.Cmd {
+	return func() tea.Msg {
+		q, err := query.Parse(text)
+		if err != nil {
+			return moreResultsMsg{tab: tab, query: text, page: page, err: err}
+		}
+		var torrents []interfaces.Torrent
+		var next string
+		err = clients.Recover(func() {
+			torrents, next = query.SearchPage(client, q, page)
+		})
+		return moreResultsMsg{tab: tab, query: text, page: page, torrents: torrents, next: next, err: err}
+	}
+}
+
+// addResults adds a page of results to the current search, skipping those
+// already found in previous pages
+func (m *Model) addResults(msg moreResultsMsg) {
+	if msg.tab != m.tabs[m.activeTab].id {
+		// the page is fetched again if the tab is shown
+		if i, ok := m.tabIndex(msg.tab); ok && m.tabs[i].query == msg.query && m.tabs[i].nextPage == msg.page {
+			m.tabs[i].loadingMore = false
+		}
+		return
+	}
+	// the search changed while the page was being fetched
+	if msg.query != m.query || msg.page != m.nextPage {
+		return
+	}
+	m.loadingMore = false
+	if msg.err != nil {
+		m.errorMessage = "Error while loading more results: " + msg.err.Error()
+		return
+	}
+	m.nextPage = msg.next
+
+	known := make(map[string]bool, len(m.results))
+	for _, t := range m.results {
+		known[t.Key()] = true
+	}
+	for _, t := range msg.torrents {
+		if !known[t.Key()] {
+			known[t.Key()] = true
+			m.results = append(m.results, t)
+		}
+	}
+
+	// bookmarks use m.torrents, results are shown once they're left
+	if m.listMode == List {
+		m.applyFilterAndSort()
+	}
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+const (
+	// previewDelay is how long the cursor has to stay on a torrent before
+	// its preview is fetched, so scrolling through the list doesn't fetch
+	// every torrent on the way
+	previewDelay = 300 * time.Millisecond
+	// sideBySideWidth is the narrowest terminal where the preview is shown
+	// next to the list instead of under it
+	sideBySideWidth = 120
+)
+
+// Preview is the state of the preview pane.
+type Preview struct {
+	Show bool
+	// Files shows the torrent's files instead of its description
+	Files bool
+}
+
+// previewEntry is a torrent's description or files, fetched for the preview
+// pane
+type previewEntry struct {
+	description string
+	files       []interfaces.TorrentFile
+	err         error
+}
+
+// previewTickMsg is sent once the cursor has stayed on a torrent for
+// previewDelay
+type previewTickMsg struct{ key string }
+
+// previewMsg carries a fetched description or file list
+type previewMsg struct {
+	key   string
+	entry previewEntry
+}
+
+// previewKey identifies the current content of the preview pane for a
+// torrent in the preview cache, which also keeps the descriptions and files
+// shown in full
+func (m *Model) previewKey(t interfaces.Torrent) string {
+	if m.preview.Files {
+		return "files:" + t.Key()
+	}
+	return "description:" + t.Key()
+}
+
+// showPreview returns true if the preview pane is visible
+func (m *Model) showPreview() bool {
+	return m.preview.Show && (m.mode == List || m.mode == Bookmarks)
+}
+
+// schedulePreview waits for previewDelay before fetching the preview of the
+// torrent under the cursor, unless it's already known
+func (m *Model) schedulePreview() tea.Cmd {
+	if !m.showPreview() || len(m.torrents) == 0 {
+		return nil
+	}
+	t := m.getCurrentTorrent()
+	key := m.previewKey(*t)
+	if key == m.previewTarget {
+		return nil
+	}
+	m.previewTarget = key
+	m.previewViewport.GotoTop()
+	if _, ok := m.previewCache[key]; ok || t.Client == nil {
+		return nil
+	}
+	if (m.preview.Files && t.Files != nil) || (!m.preview.Files && t.Description != "") {
+		return nil
+	}
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewTickMsg{key}
+	})
+}
+
+// fetchPreview fetches the preview of the torrent under the cursor, if it's
+// still the one the tick was scheduled for
+func (m *Model) fetchPreview(msg previewTickMsg) tea.Cmd {
+	if msg.key != m.previewTarget || m.previewLoading[msg.key] || len(m.torrents) == 0 {
+		return nil
+	}
+	m.previewLoading[msg.key] = true
+	return cmdFetchPreview(msg.key, *m.getCurrentTorrent(), m.preview.Files)
+}
+
+func cmdFetchPreview(key string, t interfaces.Torrent, files bool) tea.Cmd {
+	return func() tea.Msg {
+		var entry previewEntry
+		entry.err = clients.Recover(func() {
+			if files {
+				entry.files = t.FetchFiles()
+			} else {
+				entry.description = t.FetchDescription()
+			}
+		})
+		return previewMsg{key, entry}
+	}
+}
+
+// addPreview caches a fetched preview
+func (m *Model) addPreview(msg previewMsg) {
+	delete(m.previewLoading, msg.key)
+	m.previewCache[msg.key] = msg.entry
+}
+
+// cachedDescription returns the description of a torrent if it was fetched
+// before
+func (m *Model) cachedDescription(t interfaces.Torrent) (string, bool) {
+	entry, ok := m.previewCache["description:"+t.Key()]
+	return entry.description, ok && entry.err == nil
+}
+
+// cachedFiles returns the files of a torrent if they were fetched before
+func (m *Model) cachedFiles(t interfaces.Torrent) ([]interfaces.TorrentFile, bool) {
+	entry, ok := m.previewCache["files:"+t.Key()]
+	return entry.files, ok && entry.err == nil
+}
+
+// togglePreview shows or hides the preview pane, and saves the change
+func (m *Model) togglePreview() {
+	m.preview.Show = !m.preview.Show
+	m.previewTarget = ""
+	// failed previews are fetched again
+	for key, entry := range m.previewCache {
+		if entry.err != nil {
+			delete(m.previewCache, key)
+		}
+	}
+	m.savePreview()
+}
+
+// switchPreview changes between the description and the files in the
+// preview pane
+func (m *Model) switchPreview() {
+	if !m.preview.Show {
+		return
+	}
+	m.preview.Files = !m.preview.Files
+	m.previewTarget = ""
+	m.savePreview()
+}
+
+func (m *Model) savePreview() {
+	if m.storePreview == nil {
+		return
+	}
+	if err := m.storePreview(m.preview); err != nil {
+		m.errorMessage = "Error while saving preview settings: " + err.Error()
+	}
+}
+
+// previewContent returns the contents of the preview pane
+func (m *Model) previewContent() string {
+	if len(m.torrents) == 0 {
+		return ""
+	}
+	t := *m.getCurrentTorrent()
+	title := m.theme.Header.Render(t.Title) + "\n"
+	if t.Client == nil {
+		return title + "This torrent's provider is not available"
+	}
+
+	if m.preview.Files {
+		files := t.Files
+		if files == nil {
+			entry, ok := m.previewCache[m.previewKey(t)]
+			switch {
+			case !ok:
+				return title + "Loading files..."
+			case entry.err != nil:
+				return title + m.theme.Error.Render("Error while loading files: "+entry.err.Error())
+			}
+			files = entry.files
+		}
+		return title + filesTable(files)
+	}
+
+	if t.Description == "" {
+		entry, ok := m.previewCache[m.previewKey(t)]
+		switch {
+		case !ok:
+			return title + "Loading description..."
+		case entry.err != nil:
+			return title + m.theme.Error.Render("Error while loading description: "+entry.err.Error())
+		}
+		t.Description = entry.description
+	}
+	return title + m.describe(t, m.previewViewport.Width)
+}
+
+// layout sets the size of the list and the preview pane, which are next to
+// each other in wide terminals and one above the other otherwise
+func (m *Model) layout() {
+	headerHeight := lipgloss.Height(m.headerView())
+	footerHeight := lipgloss.Height(m.footerView())
+	height := m.height - headerHeight - footerHeight
+
+	m.viewport.YPosition = headerHeight
+	m.viewport.Width = m.width
+	m.viewport.Height = height
+	if !m.showPreview() {
+		return
+	}
+
+	m.previewSideBySide = m.width >= sideBySideWidth
+	if m.previewSideBySide {
+		// one column is used by the divider
+		m.viewport.Width = m.width / 2
+		m.previewViewport.Width = m.width - m.viewport.Width - 1
+		m.previewViewport.Height = height
+	} else {
+		// one line is used by the divider
+		m.viewport.Height = height / 2
+		m.previewViewport.Width = m.width
+		m.previewViewport.Height = height - m.viewport.Height - 1
+	}
+}
+
+// contentView returns the list, with the preview pane if it's visible
+func (m *Model) contentView() string {
+	if !m.showPreview() {
+		return m.viewport.View()
+	}
+	if m.previewSideBySide {
+		divider := strings.TrimSuffix(strings.Repeat("│\n", m.viewport.Height), "\n")
+		// short lines are padded so the divider is straight
+		list := lipgloss.NewStyle().Width(m.viewport.Width).Render(m.viewport.View())
+		return lipgloss.JoinHorizontal(lipgloss.Top, list, m.theme.Footer.Render(divider), m.previewViewport.View())
+	}
+	divider := m.theme.Footer.Render(strings.Repeat("─", m.width))
+	return m.viewport.View() + "\n" + divider + "\n" + m.previewViewport.View()
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/history"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/query"
+)
+
+// search starts a query in the active tab, the results arrive in a
+// searchResultsMsg. It returns false if the query isn't valid.
+func (m *Model) search(text string) (tea.Cmd, bool) {
+	q, err := query.Parse(text)
+	if err != nil {
+		m.errorMessage = "Invalid query: " + err.Error()
+		return nil, false
+	}
+	var providers []interfaces.Client
+	for _, name := range q.Providers {
+		client, err := clients.Get(name)
+		if err != nil {
+			m.errorMessage = "Invalid query: " + err.Error()
+			return nil, false
+		}
+		providers = append(providers, client)
+	}
+
+	m.query = text
+	m.nextPage = ""
+	m.loadingMore = false
+	m.searching = true
+	m.setResults(nil)
+	return cmdSearch(m.tabs[m.activeTab].id, m.client, text, q, providers), true
+}
+
+// cmdSearch searches a query in the providers it names, or in client if it
+// names none. Results of more than one provider aren't paginated.
+func cmdSearch(tab int, client interfaces.Client, text string, q query.Query, providers []interfaces.Client) tea.Cmd {
+	return func() tea.Msg {
+		if len(providers) > 0 {
+			torrents, errs := clients.SearchQuery(providers, q)
+			return searchResultsMsg{tab: tab, query: text, provider: client.Name(), torrents: torrents, errs: errs}
+		}
+		var torrents []interfaces.Torrent
+		var next string
+		err := clients.Recover(func() {
+			torrents, next = query.SearchPage(client, q, "")
+		})
+		if err != nil {
+			return searchResultsMsg{tab: tab, query: text, errs: []error{err}}
+		}
+		return searchResultsMsg{tab: tab, query: text, provider: client.Name(), torrents: torrents, next: next}
+	}
+}
+
+// showResults shows the results of a search in its tab and records the
+// search in the history
+func (m *Model) showResults(msg searchResultsMsg) {
+	if len(msg.errs) > 0 {
+		messages := make([]string, len(msg.errs))
+		for i, err := range msg.errs {
+			messages[i] = err.Error()
+		}
+		m.errorMessage = "Error while searching: " + strings.Join(messages, ", ")
+	}
+
+	if msg.tab != m.tabs[m.activeTab].id {
+		i, ok := m.tabIndex(msg.tab)
+		if !ok || !m.tabs[i].searching || m.tabs[i].query != msg.query {
+			return
+		}
+		t := &m.tabs[i]
+		t.searching = false
+		t.results = msg.torrents
+		t.torrents = filterAndSort(msg.torrents, t.filter, t.sortField, t.sortDescending)
+		t.cursorPosition = 0
+		t.nextPage = msg.next
+	} else {
+		// the search changed while it was running
+		if !m.searching || msg.query != m.query {
+			return
+		}
+		m.searching = false
+		m.nextPage = msg.next
+		if m.listMode == List {
+			m.setResults(msg.torrents)
+		} else {
+			// bookmarks use m.torrents, results are shown once they're left
+			m.results = msg.torrents
+			m.listCursor = 0
+		}
+	}
+
+	if m.history == nil || msg.query == "" || len(msg.errs) > 0 && len(msg.torrents) == 0 {
+		return
+	}
+	err := m.history.Add(history.Entry{
+		Query:    msg.query,
+		Provider: msg.provider,
+		Time:     time.Now(),
+		Results:  len(msg.torrents),
+	})
+	if err != nil {
+		m.errorMessage = "Error while saving search history: " + err.Error()
+	}
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// fakeClient finds a torrent named after the query
+type fakeClient struct{}
+
+func (fakeClient) Name() string { return "fake" }
+
+func (c fakeClient) Search(query string) []interfaces.Torrent {
+	torrents, _ := c.SearchPage(query, "")
+	return torrents
+}
+
+func (fakeClient) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	if query == "fail" {
+		panic("search failed")
+	}
+	return []interfaces.Torrent{{Title: query, InfoHash: query}}, "2"
+}
+
+func (fakeClient) NavigateTo(interfaces.Torrent) {}
+
+func (fakeClient) FetchTorrentDescription(interfaces.Torrent) string { return "" }
+
+func (fakeClient) FetchTorrentFiles(interfaces.Torrent) []interfaces.TorrentFile { return nil }
+
+func newTestModel() *Model {
+	return &Model{client: fakeClient{}, listMode: List, tabs: []tab{{}}}
+}
+
+func TestSearch(t *testing.T) {
+	m := newTestModel()
+	cmd, ok := m.search("ubuntu")
+	if !ok || cmd == nil {
+		t.Fatalf("search() = %v, %t, want a command", cmd, ok)
+	}
+	if !m.searching || len(m.torrents) != 0 {
+		t.Errorf("the search isn't shown as running")
+	}
+
+	m.showResults(cmd().(searchResultsMsg))
+	if m.searching || len(m.torrents) != 1 || m.torrents[0].Title != "ubuntu" || m.nextPage != "2" {
+		t.Errorf("results = %+v, next page %q, want ubuntu and page 2", m.torrents, m.nextPage)
+	}
+}
+
+func TestSearchInvalidQuery(t *testing.T) {
+	m := newTestModel()
+	if cmd, ok := m.search("provider:nope ubuntu"); ok || cmd != nil {
+		t.Errorf("search() of an unknown provider = %v, %t, want none", cmd, ok)
+	}
+	if m.errorMessage == "" {
+		t.Error("no error was shown")
+	}
+}
+
+func TestSearchFails(t *testing.T) {
+	m := newTestModel()
+	cmd, _ := m.search("fail")
+	m.showResults(cmd().(searchResultsMsg))
+	if m.searching || m.errorMessage == "" {
+		t.Errorf("searching = %t, error %q, want the search to end with an error", m.searching, m.errorMessage)
+	}
+}
+
+func TestSearchResultsOfOtherSearches(t *testing.T) {
+	m := newTestModel()
+	old, _ := m.search("old")
+	current, _ := m.search("current")
+
+	// results of a replaced search are dropped
+	m.showResults(old().(searchResultsMsg))
+	if !m.searching || len(m.torrents) != 0 {
+		t.Errorf("results of the old search were shown: %+v", m.torrents)
+	}
+
+	// results of a search in another tab go to that tab
+	m.saveTab()
+	m.tabs = append(m.tabs, tab{id: 1, client: fakeClient{}})
+	m.loadTab(1)
+	m.showResults(current().(searchResultsMsg))
+	if len(m.torrents) != 0 {
+		t.Errorf("results of another tab were shown: %+v", m.torrents)
+	}
+	m.switchTab(-1)
+	if m.searching || len(m.torrents) != 1 || m.torrents[0].Title != "current" {
+		t.Errorf("results of the first tab = %+v, want current", m.torrents)
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+type sortField int
+
+const (
+	sortNone sortField = iota
+	sortTitle
+	sortSize
+	sortSeeders
+	sortLeechers
+	sortUploaded
+	sortResolution
+	sortCodec
+	sortSeasonEpisode
+	sortFieldCount
+)
+
+var sortFieldNames = map[sortField]string{
+	sortNone:          "none",
+	sortTitle:         "title",
+	sortSize:          "size",
+	sortSeeders:       "seeders",
+	sortLeechers:      "leechers",
+	sortUploaded:      "uploaded",
+	sortResolution:    "resolution",
+	sortCodec:         "codec",
+	sortSeasonEpisode: "season/episode",
+}
+
+func (s sortField) String() string {
+	return sortFieldNames[s]
+}
+
+// next returns the field to sort by after this one, cycling through all of
+// them
+func (s sortField) next() sortField {
+	return (s + 1) % sortFieldCount
+}
+
+func (s sortField) less(a, b interfaces.Torrent) bool {
+	switch s {
+	case sortTitle:
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	case sortSize:
+		return a.Size < b.Size
+	case sortSeeders:
+		return a.Seeders < b.Seeders
+	case sortLeechers:
+		return a.Leechers < b.Leechers
+	case sortUploaded:
+		aDate, _ := strconv.ParseInt(a.Uploaded, 10, 64)
+		bDate, _ := strconv.ParseInt(b.Uploaded, 10, 64)
+		return aDate < bDate
+	case sortResolution:
+		return a.Release.ResolutionHeight() < b.Release.ResolutionHeight()
+	case sortCodec:
+		return a.Release.Codec < b.Release.Codec
+	case sortSeasonEpisode:
+		if a.Release.Season != b.Release.Season {
+			return a.Release.Season < b.Release.Season
+		}
+		return a.Release.Episode < b.Release.Episode
+	default:
+		return false
+	}
+}
+
+// sortTorrents sorts torrents in place. Sorting is stable, so torrents that
+// compare equal keep the order given by the client.
+func sortTorrents(torrents []interfaces.Torrent, field sortField, descending bool) {
+	if field == sortNone {
+		return
+	}
+	sort.SliceStable(torrents, func(i, j int) bool {
+		if descending {
+			return field.less(torrents[j], torrents[i])
+		}
+		return field.less(torrents[i], torrents[j])
+	})
+}
@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// minTitleWidth is the narrowest the title column gets in small terminals
+	minTitleWidth = 10
+	// defaultTitleWidth is used before the terminal's width is known
+	defaultTitleWidth = 64
+	ellipsis          = "…"
+)
+
+// a column of a table
+type column struct {
+	header string
+	// width is the column's width, 0 for a column that takes the space left
+	// by the others
+	width int
+	// right aligns the column, numbers are right aligned and text is left
+	// aligned
+	right bool
+	value func(m *Model, i int, t interfaces.Torrent) string
+	// style colors the column's cells, nil uses the row's style
+	style func(m *Model, t interfaces.Torrent) lipgloss.Style
+}
+
+// torrentColumns are the columns that can be shown in the results table
+var torrentColumns = map[string]column{
+	"badge": {"!", 2, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return m.assessor.Assess(t).Badge()
+	}, func(m *Model, t interfaces.Torrent) lipgloss.Style {
+		return m.theme.Badge(m.assessor.Assess(t).Severity())
+	}},
+	"no": {"No.", 3, true, func(m *Model, i int, t interfaces.Torrent) string {
+		return strconv.Itoa(i)
+	}, nil},
+	"title": {"Title", 0, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return t.Title
+	}, nil},
+	"size": {"Size", 9, true, func(m *Model, i int, t interfaces.Torrent) string {
+		return t.GetPrettySize()
+	}, nil},
+	"seeders": {"S", 4, true, func(m *Model, i int, t interfaces.Torrent) string {
+		return strconv.Itoa(t.Seeders)
+	}, func(m *Model, t interfaces.Torrent) lipgloss.Style {
+		return m.theme.Seeders(t.Seeders)
+	}},
+	"leechers": {"L", 4, true, func(m *Model, i int, t interfaces.Torrent) string {
+		return strconv.Itoa(t.Leechers)
+	}, nil},
+	"uploaded": {"Uploaded", 10, false, func(m *Model, i int, t interfaces.Torrent) string {
+		dateInt, err := strconv.ParseInt(t.Uploaded, 10, 64)
+		if err != nil {
+			return "err"
+		}
+		return time.Unix(dateInt, 0).Format("2006-01-02")
+	}, nil},
+	"provider": {"Provider", 8, false, func(m *Model, i int, t interfaces.Torrent) string {
+		if t.Client == nil {
+			return ""
+		}
+		return t.Client.Name()
+	}, nil},
+	"res": {"Res", 5, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return t.Release.Resolution
+	}, nil},
+	"codec": {"Codec", 6, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return t.Release.Codec
+	}, nil},
+	"se": {"S/E", 6, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return t.Release.SeasonEpisode()
+	}, nil},
+}
+
+// DefaultColumns are the columns shown in the results table when none are
+// configured
+var DefaultColumns = []string{"badge", "no", "title", "size", "seeders", "leechers", "uploaded"}
+
+// bookmarkColumns are the columns of the bookmarks table
+var bookmarkColumns = []column{
+	torrentColumns["no"],
+	torrentColumns["title"],
+	torrentColumns["size"],
+	// the stored provider is shown even if it isn't configured anymore
+	{"Provider", 8, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return m.bookmarkList[i].Provider
+	}, nil},
+	{"Note", 20, false, func(m *Model, i int, t interfaces.Torrent) string {
+		return m.bookmarkList[i].Note
+	}, nil},
+}
+
+// CheckColumns returns an error if a column can't be shown in the results
+// table
+func CheckColumns(names []string) error {
+	for _, name := range names {
+		if _, ok := torrentColumns[name]; !ok {
+			return fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return nil
+}
+
+// visibleColumns returns the columns of the results table, in order. Only
+// the named columns are shown, or the default ones if none are named.
+func visibleColumns(names []string) []column {
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+
+	var columns []column
+	for _, name := range names {
+		if c, ok := torrentColumns[name]; ok {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// columnWidths returns the width of each column in a table as wide as width.
+// Columns without a width share the space left by the others.
+func columnWidths(columns []column, width int) []int {
+	widths := make([]int, len(columns))
+	// the cursor takes one character
+	fixed := 1
+	flexible := 0
+	for i, c := range columns {
+		widths[i] = c.width
+		// every column is preceded by a space
+		fixed += c.width + 1
+		if c.width == 0 {
+			flexible++
+		}
+	}
+	if flexible == 0 {
+		return widths
+	}
+
+	share := defaultTitleWidth
+	if width > 0 {
+		share = (width - fixed) / flexible
+	}
+	if share < minTitleWidth {
+		share = minTitleWidth
+	}
+	for i, c := range columns {
+		if c.width == 0 {
+			widths[i] = share
+		}
+	}
+	return widths
+}
+
+// renderTable renders the given torrents in a table that fits the viewport
+func (m *Model) renderTable(columns []column, torrents []interfaces.Torrent) string {
+	widths := columnWidths(columns, m.viewport.Width)
+
+	header := " "
+	for i, c := range columns {
+		header += " " + fit(c.header, widths[i], c.right)
+	}
+	s := m.theme.Header.Render(header) + "\n"
+
+	for i, torrent := range torrents {
+		// Is the cursor pointing at this choice?
+		cursor := " "
+		if m.cursorPosition == i {
+			cursor = ">"
+		}
+
+		style := m.rowStyle(i)
+		row := style.Render(cursor)
+		for j, c := range columns {
+			cell := fit(c.value(m, i, torrent), widths[j], c.right)
+			if c.style != nil {
+				row += style.Render(" ") + m.cellStyle(i, c.style(m, torrent)).Render(cell)
+			} else {
+				row += style.Render(" " + cell)
+			}
+		}
+		s += row + "\n"
+	}
+	return s
+}
+
+// fit truncates s with an ellipsis if it's wider than width, and pads it to
+// width otherwise
+func fit(s string, width int, right bool) string {
+	s = runewidth.Truncate(s, width, ellipsis)
+	if right {
+		return runewidth.FillLeft(s, width)
+	}
+	return runewidth.FillRight(s, width)
+}
+
+// rowStyle returns the style of the i-th row of a table, which depends on
+// whether it's selected and alternates between rows
+func (m *Model) rowStyle(i int) lipgloss.Style {
+	switch {
+	case i == m.cursorPosition:
+		return m.theme.Selected
+	case i%2 == 1:
+		return m.theme.Zebra
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// cellStyle returns the style of a colored cell in the i-th row. The
+// selected row is rendered in a single style so it stands out.
+func (m *Model) cellStyle(i int, cell lipgloss.Style) lipgloss.Style {
+	if i == m.cursorPosition {
+		return m.theme.Selected
+	}
+	return cell.Copy().Inherit(m.rowStyle(i))
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func columnHeaders(columns []column) []string {
+	var headers []string
+	for _, c := range columns {
+		headers = append(headers, c.header)
+	}
+	return headers
+}
+
+func TestVisibleColumns(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{nil, []string{"!", "No.", "Title", "Size", "S", "L", "Uploaded"}},
+		// only the listed columns are shown, in their order
+		{[]string{"res", "codec", "se"}, []string{"Res", "Codec", "S/E"}},
+		{[]string{"title", "res", "no"}, []string{"Title", "Res", "No."}},
+	}
+	for _, tt := range tests {
+		if got := columnHeaders(visibleColumns(tt.names)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("visibleColumns(%q) = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+
+	if err := CheckColumns([]string{"title", "nope"}); err == nil {
+		t.Error("CheckColumns() accepted an unknown column")
+	}
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/filter"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/mattn/go-runewidth"
+)
+
+// maxTabTitleWidth is the widest a tab's title is in the tab bar
+const maxTabTitleWidth = 24
+
+// tab is a search with its own results, cursor, filter and sort. The active
+// tab's state is kept in the model's fields, and saved when another tab is
+// shown.
+type tab struct {
+	id             int
+	client         interfaces.Client
+	query          string
+	results        []interfaces.Torrent
+	torrents       []interfaces.Torrent
+	cursorPosition int
+	filter         filter.Filter
+	filterText     string
+	sortField      sortField
+	sortDescending bool
+	nextPage       string
+	searching      bool
+	loadingMore    bool
+}
+
+// saveTab saves the active tab's state
+func (m *Model) saveTab() {
+	m.tabs[m.activeTab] = tab{
+		id:             m.tabs[m.activeTab].id,
+		client:         m.client,
+		query:          m.query,
+		results:        m.results,
+		torrents:       m.torrents,
+		cursorPosition: m.cursorPosition,
+		filter:         m.filter,
+		filterText:     m.filterInput.Value(),
+		sortField:      m.sortField,
+		sortDescending: m.sortDescending,
+		nextPage:       m.nextPage,
+		searching:      m.searching,
+		loadingMore:    m.loadingMore,
+	}
+}
+
+// loadTab makes the i-th tab the active one
+func (m *Model) loadTab(i int) {
+	t := m.tabs[i]
+	m.activeTab = i
+	m.client = t.client
+	m.query = t.query
+	m.results = t.results
+	m.torrents = t.torrents
+	m.cursorPosition = t.cursorPosition
+	m.filter = t.filter
+	m.filterInput.SetValue(t.filterText)
+	m.sortField = t.sortField
+	m.sortDescending = t.sortDescending
+	m.nextPage = t.nextPage
+	m.searching = t.searching
+	m.loadingMore = t.loadingMore
+	m.input = ""
+	m.previewTarget = ""
+}
+
+// switchTab shows the tab step tabs away from the active one
+func (m *Model) switchTab(step int) {
+	if len(m.tabs) < 2 {
+		return
+	}
+	m.saveTab()
+	m.loadTab((m.activeTab + step + len(m.tabs)) % len(m.tabs))
+}
+
+// newTab opens a tab for a new search, using the active tab's provider
+func (m *Model) newTab() tea.Cmd {
+	m.saveTab()
+	m.nextTabID++
+	m.tabs = append(m.tabs, tab{id: m.nextTabID, client: m.client})
+	m.loadTab(len(m.tabs) - 1)
+	return m.enterSearchMode()
+}
+
+// closeTab closes the active tab, unless it's the only one
+func (m *Model) closeTab() {
+	if len(m.tabs) < 2 {
+		m.message = "The last tab can't be closed"
+		return
+	}
+	m.tabs = append(m.tabs[:m.activeTab], m.tabs[m.activeTab+1:]...)
+	i := m.activeTab
+	if i == len(m.tabs) {
+		i--
+	}
+	m.loadTab(i)
+}
+
+// tabIndex returns the position of the tab with the given id
+func (m *Model) tabIndex(id int) (int, bool) {
+	for i, t := range m.tabs {
+		if t.id == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// tabTitle is how a tab is shown in the tab bar, such as "2 tpb: ubuntu"
+func (m *Model) tabTitle(i int) string {
+	client, query := m.tabs[i].client, m.tabs[i].query
+	if i == m.activeTab {
+		client, query = m.client, m.query
+	}
+	if query == "" {
+		query = "new search"
+	}
+	title := fmt.Sprintf("%d %s", i+1, query)
+	if client != nil {
+		title = fmt.Sprintf("%d %s: %s", i+1, client.Name(), query)
+	}
+	return runewidth.Truncate(title, maxTabTitleWidth, ellipsis)
+}
+
+// tabBar shows the tabs, or nothing if there's only one
+func (m *Model) tabBar() string {
+	if len(m.tabs) < 2 {
+		return ""
+	}
+	var titles []string
+	for i := range m.tabs {
+		title := " " + m.tabTitle(i) + " "
+		if i == m.activeTab {
+			titles = append(titles, m.theme.Selected.Render(title))
+		} else {
+			titles = append(titles, m.theme.Footer.Render(title))
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, titles...)
+}
+
+// tabAt returns the tab shown at x in the tab bar
+func (m *Model) tabAt(x int) (int, bool) {
+	start := 0
+	for i := range m.tabs {
+		width := runewidth.StringWidth(m.tabTitle(i)) + 2
+		if x >= start && x < start+width {
+			return i, true
+		}
+		start += width
+	}
+	return 0, false
+}
@@ -0,0 +1,237 @@
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/risk"
+)
+
+// Colors are the colors of a theme, either ANSI color numbers such as "12"
+// or hex colors such as "#ff8800". Empty colors aren't used.
+type Colors struct {
+	Header             string `mapstructure:"header"`
+	Selected           string `mapstructure:"selected"`
+	SelectedBackground string `mapstructure:"selected-background"`
+	// Zebra is the background of every other row
+	Zebra         string `mapstructure:"zebra"`
+	SeedersHigh   string `mapstructure:"seeders-high"`
+	SeedersMedium string `mapstructure:"seeders-medium"`
+	SeedersLow    string `mapstructure:"seeders-low"`
+	BadgeLow      string `mapstructure:"badge-low"`
+	BadgeMedium   string `mapstructure:"badge-medium"`
+	BadgeHigh     string `mapstructure:"badge-high"`
+	Footer        string `mapstructure:"footer"`
+	Error         string `mapstructure:"error"`
+	Input         string `mapstructure:"input"`
+}
+
+// Theme holds the styles used by the UI.
+type Theme struct {
+	Header        lipgloss.Style
+	Selected      lipgloss.Style
+	Zebra         lipgloss.Style
+	SeedersHigh   lipgloss.Style
+	SeedersMedium lipgloss.Style
+	SeedersLow    lipgloss.Style
+	BadgeLow      lipgloss.Style
+	BadgeMedium   lipgloss.Style
+	BadgeHigh     lipgloss.Style
+	Footer        lipgloss.Style
+	Error         lipgloss.Style
+	Input         lipgloss.Style
+}
+
+// Default is the name of the theme used when none is configured.
+const Default = "dark"
+
+// Builtin are the themes that can be used by name.
+var Builtin = map[string]Colors{
+	"dark": {
+		Header:        "15",
+		Selected:      "12",
+		Zebra:         "235",
+		SeedersHigh:   "10",
+		SeedersMedium: "11",
+		SeedersLow:    "9",
+		BadgeLow:      "11",
+		BadgeMedium:   "208",
+		BadgeHigh:     "9",
+		Footer:        "245",
+		Error:         "9",
+		Input:         "12",
+	},
+	"light": {
+		Header:        "0",
+		Selected:      "4",
+		Zebra:         "254",
+		SeedersHigh:   "28",
+		SeedersMedium: "130",
+		SeedersLow:    "160",
+		BadgeLow:      "130",
+		BadgeMedium:   "166",
+		BadgeHigh:     "160",
+		Footer:        "240",
+		Error:         "160",
+		Input:         "4",
+	},
+	"high-contrast": {
+		Header:             "15",
+		Selected:           "0",
+		SelectedBackground: "11",
+		SeedersHigh:        "10",
+		SeedersMedium:      "11",
+		SeedersLow:         "9",
+		BadgeLow:           "11",
+		BadgeMedium:        "11",
+		BadgeHigh:          "9",
+		Footer:             "15",
+		Error:              "9",
+		Input:              "11",
+	},
+}
+
+// Get returns the built-in theme with the given name, with its colors
+// replaced by the non-empty colors in custom. It returns an error if a custom
+// color isn't an ANSI color number or a hex color.
+func Get(name string, custom Colors) (Theme, error) {
+	if name == "" {
+		name = Default
+	}
+	colors, ok := Builtin[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, available themes are %v", name, Names())
+	}
+	colors = merge(colors, custom)
+	for _, c := range colors.list() {
+		if *c.value != "" && !validColor(*c.value) {
+			return Theme{}, fmt.Errorf("invalid color %q for %s, use an ANSI color number such as \"12\" or a hex color such as \"#ff8800\"", *c.value, c.name)
+		}
+	}
+	return New(colors), nil
+}
+
+// New creates a theme with the given colors.
+func New(c Colors) Theme {
+	return Theme{
+		Header:        foreground(c.Header).Bold(true),
+		Selected:      foreground(c.Selected).Background(color(c.SelectedBackground)).Bold(true),
+		Zebra:         lipgloss.NewStyle().Background(color(c.Zebra)),
+		SeedersHigh:   foreground(c.SeedersHigh),
+		SeedersMedium: foreground(c.SeedersMedium),
+		SeedersLow:    foreground(c.SeedersLow),
+		BadgeLow:      foreground(c.BadgeLow),
+		BadgeMedium:   foreground(c.BadgeMedium).Bold(true),
+		BadgeHigh:     foreground(c.BadgeHigh).Bold(true),
+		Footer:        foreground(c.Footer),
+		Error:         foreground(c.Error).Bold(true),
+		Input:         foreground(c.Input),
+	}
+}
+
+// Plain returns a theme without colors, for terminals where NO_COLOR is set.
+// The selected row is still marked.
+func Plain() Theme {
+	return Theme{
+		Header:   lipgloss.NewStyle().Bold(true),
+		Selected: lipgloss.NewStyle().Reverse(true),
+		Error:    lipgloss.NewStyle().Bold(true),
+	}
+}
+
+// Seeders returns the style for a number of seeders, depending on how
+// healthy the swarm is.
+func (t Theme) Seeders(seeders int) lipgloss.Style {
+	switch {
+	case seeders >= 20:
+		return t.SeedersHigh
+	case seeders >= 5:
+		return t.SeedersMedium
+	default:
+		return t.SeedersLow
+	}
+}
+
+// Badge returns the style for the risk badge of a torrent.
+func (t Theme) Badge(severity risk.Severity) lipgloss.Style {
+	switch severity {
+	case risk.High:
+		return t.BadgeHigh
+	case risk.Medium:
+		return t.BadgeMedium
+	default:
+		return t.BadgeLow
+	}
+}
+
+// Names returns the names of the built-in themes.
+func Names() []string {
+	names := make([]string, 0, len(Builtin))
+	for name := range Builtin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namedColor is a color of a theme along with its name in the config
+type namedColor struct {
+	name  string
+	value *string
+}
+
+// list returns the colors of a theme, in the order of the struct
+func (c *Colors) list() []namedColor {
+	return []namedColor{
+		{"header", &c.Header},
+		{"selected", &c.Selected},
+		{"selected-background", &c.SelectedBackground},
+		{"zebra", &c.Zebra},
+		{"seeders-high", &c.SeedersHigh},
+		{"seeders-medium", &c.SeedersMedium},
+		{"seeders-low", &c.SeedersLow},
+		{"badge-low", &c.BadgeLow},
+		{"badge-medium", &c.BadgeMedium},
+		{"badge-high", &c.BadgeHigh},
+		{"footer", &c.Footer},
+		{"error", &c.Error},
+		{"input", &c.Input},
+	}
+}
+
+func merge(base Colors, custom Colors) Colors {
+	customColors := custom.list()
+	for i, c := range base.list() {
+		if *customColors[i].value != "" {
+			*c.value = *customColors[i].value
+		}
+	}
+	return base
+}
+
+// hexColorRegexp matches colors such as "#f80" or "#ff8800"
+var hexColorRegexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor returns true if c is an ANSI color number from 0 to 255 or a hex
+// color
+func validColor(c string) bool {
+	if n, err := strconv.Atoi(c); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColorRegexp.MatchString(c)
+}
+
+func foreground(c string) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(color(c))
+}
+
+// color returns no color for an empty string
+func color(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
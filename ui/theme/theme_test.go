@@ -0,0 +1,76 @@
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/risk"
+)
+
+func TestGet(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		if _, err := Get(name, Colors{}); err != nil {
+			t.Errorf("Get(%q) = %v", name, err)
+		}
+	}
+
+	_, err := Get("solarized", Colors{})
+	if err == nil || !strings.Contains(err.Error(), `unknown theme "solarized"`) || !strings.Contains(err.Error(), "high-contrast") {
+		t.Errorf("Get() of an unknown theme = %v, want an error listing the themes", err)
+	}
+}
+
+func TestGetCustomColors(t *testing.T) {
+	got, err := Get("light", Colors{Header: "#ff8800", Zebra: "#abc", SeedersLow: "255"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Header.GetForeground() != lipgloss.Color("#ff8800") || got.Zebra.GetBackground() != lipgloss.Color("#abc") {
+		t.Errorf("custom colors weren't used: %v, %v", got.Header.GetForeground(), got.Zebra.GetBackground())
+	}
+	// the other colors are the theme's
+	if got.Footer.GetForeground() != lipgloss.Color(Builtin["light"].Footer) {
+		t.Errorf("footer = %v, want the light theme's", got.Footer.GetForeground())
+	}
+	if Builtin["light"].Header != "0" {
+		t.Error("custom colors changed the built-in theme")
+	}
+}
+
+func TestGetBadColor(t *testing.T) {
+	for _, c := range []string{"red", "256", "-1", "#ff88", "ff8800", "#gg8800"} {
+		_, err := Get("dark", Colors{BadgeHigh: c})
+		if err == nil || !strings.Contains(err.Error(), "badge-high") {
+			t.Errorf("Get() with color %q = %v, want an error naming badge-high", c, err)
+		}
+	}
+}
+
+func TestPlain(t *testing.T) {
+	p := Plain()
+	if !p.Selected.GetReverse() {
+		t.Error("the selected row isn't marked")
+	}
+	for _, s := range []lipgloss.Style{p.Header, p.Selected, p.Zebra, p.Seeders(30), p.Seeders(0), p.Badge(risk.High), p.Footer, p.Error, p.Input} {
+		if _, ok := s.GetForeground().(lipgloss.NoColor); !ok {
+			t.Errorf("style has foreground %v, want no color", s.GetForeground())
+		}
+		if _, ok := s.GetBackground().(lipgloss.NoColor); !ok {
+			t.Errorf("style has background %v, want no color", s.GetBackground())
+		}
+	}
+}
+
+func TestSeedersAndBadge(t *testing.T) {
+	th := New(Builtin["dark"])
+	tests := map[int]lipgloss.Style{0: th.SeedersLow, 4: th.SeedersLow, 5: th.SeedersMedium, 19: th.SeedersMedium, 20: th.SeedersHigh}
+	for seeders, want := range tests {
+		if got := th.Seeders(seeders); got.GetForeground() != want.GetForeground() {
+			t.Errorf("Seeders(%d) = %v, want %v", seeders, got.GetForeground(), want.GetForeground())
+		}
+	}
+	if th.Badge(risk.High).GetForeground() != lipgloss.Color("9") || th.Badge(risk.Medium).GetForeground() != lipgloss.Color("208") {
+		t.Error("badges don't use the badge colors")
+	}
+}
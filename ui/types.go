@@ -1,10 +1,19 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ismaelpadilla/gotorrent/bookmarks"
+	"github.com/ismaelpadilla/gotorrent/filter"
+	"github.com/ismaelpadilla/gotorrent/history"
 	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/risk"
+	"github.com/ismaelpadilla/gotorrent/ui/markup"
+	"github.com/ismaelpadilla/gotorrent/ui/theme"
 )
 
 type Mode int
@@ -14,10 +23,14 @@ const (
 	ShowDescription
 	ShowFiles
 	Search
+	Filter
+	Bookmarks
+	BookmarkNote
 )
 
 type Model struct {
 	client           interfaces.Client
+	results          []interfaces.Torrent
 	torrents         []interfaces.Torrent
 	downloadLocation string
 	cursorPosition   int
@@ -29,9 +42,65 @@ type Model struct {
 	ready            bool
 	mode             Mode
 	searchInput      textinput.Model
+	filterInput      textinput.Model
+	filter           filter.Filter
+	sortField        sortField
+	sortDescending   bool
+	columns          []column
 	message          string
+	errorMessage     string
+	theme            theme.Theme
 	persist          bool
 	debug            bool
+	assessor         risk.Assessor
+	history          *history.Store
+	historyQueries   []string
+	historyIndex     int
+	draftQuery       string
+	reverseSearch    bool
+	reverseMatch     int
+	bookmarks        *bookmarks.Store
+	bookmarkList     []bookmarks.Bookmark
+	noteInput        textinput.Model
+	// listMode is the mode to go back to from ShowDescription and ShowFiles,
+	// either List or Bookmarks
+	listMode Mode
+	// previousMode is the mode to go back to once a bookmark is saved
+	previousMode Mode
+	// listCursor keeps the cursor position in the search results while
+	// bookmarks are shown
+	listCursor int
+	// query is the current search, nextPage the token of its next page of
+	// results or "" if there are no more
+	query       string
+	nextPage    string
+	searching   bool
+	loadingMore bool
+	width       int
+	// initialSearch runs the query given on the command line
+	initialSearch tea.Cmd
+	// preview is the state of the preview pane, previewTarget the key of
+	// the preview being shown
+	preview           Preview
+	previewViewport   viewport.Model
+	previewTarget     string
+	previewCache      map[string]previewEntry
+	previewLoading    map[string]bool
+	previewSideBySide bool
+	storePreview      func(Preview) error
+	// links are the links in the description being shown
+	links      []markup.Link
+	linkCursor int
+	// lastClick is when a row was last clicked, to detect double clicks
+	lastClick    time.Time
+	lastClickRow int
+	// tabs are the open searches, the active one's state is in the fields
+	// above
+	tabs      []tab
+	activeTab int
+	nextTabID int
+	// pendingKey is the first key of a sequence such as "g t"
+	pendingKey string
 }
 
 type Config struct {
@@ -39,7 +108,40 @@ type Config struct {
 	Persist        bool
 	DownloadFolder string
 	Debug          bool
+	// Columns contains the columns shown in the results table, in order.
+	// DefaultColumns are shown if it's empty.
+	Columns []string
+	Risk    risk.Config
+	// History is where searches are recorded, nil disables the history
+	History *history.Store
+	// Bookmarks is where bookmarks are saved, nil disables bookmarks
+	Bookmarks *bookmarks.Store
+	Theme     theme.Theme
+	Preview   Preview
+	// SavePreview is called when the preview pane is toggled, so it's
+	// remembered the next time
+	SavePreview func(Preview) error
 }
 
 type errMsg struct{ err error }
 type statusMsg struct{ message string }
+
+// searchResultsMsg carries the results of a search started in a tab
+type searchResultsMsg struct {
+	tab      int
+	query    string
+	provider string
+	torrents []interfaces.Torrent
+	next     string
+	errs     []error
+}
+
+// moreResultsMsg carries the next page of results of a search
+type moreResultsMsg struct {
+	tab      int
+	query    string
+	page     string
+	torrents []interfaces.Torrent
+	next     string
+	err      error
+}
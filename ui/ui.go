@@ -2,32 +2,39 @@ package ui
 
 import (
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"strconv"
-	"time"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/clients/httpclient"
+	"github.com/ismaelpadilla/gotorrent/filter"
 	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/risk"
+	"github.com/ismaelpadilla/gotorrent/torrentcache"
 	"github.com/ismaelpadilla/gotorrent/ui/keys"
+	"github.com/ismaelpadilla/gotorrent/ui/markup"
+	"github.com/mattn/go-runewidth"
 	"github.com/skratchdot/open-golang/open"
 )
 
-var selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
-
 func InitialModel(query string, config Config) Model {
 	var mode Mode
 
-	var torrents []interfaces.Torrent
 	h := help.New()
 	searchInput := textinput.New()
+	filterInput := textinput.New()
+	filterInput.Prompt = "/ "
+	noteInput := textinput.New()
+	noteInput.Prompt = "Note: "
 
 	if query == "" {
 		mode = Search
@@ -36,24 +43,46 @@ func InitialModel(query string, config Config) Model {
 	} else {
 		mode = List
 		h.View(keys.ListKeys)
-		torrents = config.Client.Search(query)
 	}
 
-	return Model{
+	m := Model{
 		client:           config.Client,
-		torrents:         torrents,
 		downloadLocation: config.DownloadFolder,
 		mode:             mode,
 		keys:             keys.ListKeys,
 		help:             h,
 		persist:          config.Persist,
 		searchInput:      searchInput,
+		filterInput:      filterInput,
 		debug:            config.Debug,
+		columns:          visibleColumns(config.Columns),
+		assessor:         risk.New(config.Risk),
+		history:          config.History,
+		bookmarks:        config.Bookmarks,
+		noteInput:        noteInput,
+		listMode:         List,
+		tabs:             []tab{{}},
+		theme:            config.Theme,
+		preview:          config.Preview,
+		previewCache:     map[string]previewEntry{},
+		previewLoading:   map[string]bool{},
+		storePreview:     config.SavePreview,
+	}
+	if mode == Search {
+		m.loadHistory()
+	} else if cmd, ok := m.search(query); ok {
+		m.initialSearch = cmd
+	} else {
+		// the query is shown so it can be fixed
+		m.enterSearchMode()
+		m.searchInput.SetValue(query)
 	}
+
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.initialSearch)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -64,12 +93,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	m.searchInput, cmd = m.searchInput.Update(msg)
 	cmds = append(cmds, cmd)
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	cmds = append(cmds, cmd)
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	cmds = append(cmds, cmd)
 
 	switch msg := msg.(type) {
 	case statusMsg:
 		m.message = msg.message
 	case errMsg:
-		m.message = msg.err.Error()
+		m.errorMessage = msg.err.Error()
+	case searchResultsMsg:
+		m.showResults(msg)
+	case moreResultsMsg:
+		m.addResults(msg)
+	case previewTickMsg:
+		cmds = append(cmds, m.fetchPreview(msg))
+	case previewMsg:
+		m.addPreview(msg)
 	case tea.KeyMsg:
 		shouldQuit, cmd := m.handleKeyPress(msg)
 		if shouldQuit {
@@ -79,12 +120,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
-		m.viewport.SetContent(m.GetContent())
+	case tea.MouseMsg:
+		shouldQuit, cmd := m.handleMouse(msg)
+		if shouldQuit {
+			return m, tea.Quit
+		}
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 
 	case tea.WindowSizeMsg:
-		headerHeight := lipgloss.Height(m.headerView())
-		footerHeight := lipgloss.Height(m.footerView())
-		verticalMarginHeight := headerHeight + footerHeight
+		m.width = msg.Width
 		m.height = msg.Height
 		m.help.Width = msg.Width
 
@@ -94,17 +140,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// we can initialize the viewport. The initial dimensions come in
 			// quickly, though asynchronously, which is why we wait for them
 			// here.
-			m.viewport = viewport.New(msg.Width, msg.Height-verticalMarginHeight)
-			m.viewport.YPosition = headerHeight
-			m.viewport.SetContent(m.GetContent())
+			m.viewport = viewport.New(msg.Width, msg.Height)
+			m.previewViewport = viewport.New(msg.Width, msg.Height)
 			m.ready = true
-		} else {
-			m.viewport.Width = msg.Width
-			m.viewport.Height = msg.Height - verticalMarginHeight
 		}
-	default:
+	}
+
+	if m.ready {
+		// the size of the viewports depends on the mode and on the
+		// terminal's size, and column widths on the viewport's width
+		m.layout()
 		m.viewport.SetContent(m.GetContent())
+		if m.showPreview() {
+			m.previewViewport.SetContent(lipgloss.NewStyle().Width(m.previewViewport.Width).Render(m.previewContent()))
+		}
 	}
+	cmds = append(cmds, m.schedulePreview())
 
 	// adjust viewport if cursor position isn't visible
 	// -1 because of the header line
@@ -121,14 +172,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 	var cmd tea.Cmd
 	m.message = ""
-	keyString := msg.String()
+	m.errorMessage = ""
+	k := keys.All
+	if key.Matches(msg, k.CtrlC) {
+		return true, nil
+	}
+	msg, pending := m.keySequence(msg)
+	if pending {
+		return false, nil
+	}
+	if len(m.torrents) == 0 && needsTorrent(msg) && (m.mode == List || m.mode == Bookmarks) {
+		return false, nil
+	}
+
 	switch m.mode {
-	case List:
-		switch keyString {
-		case "ctrl+c", "q", "esc":
+	case List, Bookmarks:
+		switch {
+		case m.mode == List && key.Matches(msg, k.QuitQEsc):
 			return true, nil
 
-		case "up", "k":
+		case m.mode == Bookmarks && key.Matches(msg, k.GoBackQEsc):
+			m.leaveBookmarks()
+
+		case key.Matches(msg, k.Up):
 			m.input = ""
 			if m.cursorPosition > 0 {
 				m.cursorPosition--
@@ -136,143 +202,323 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 				m.cursorPosition = 0
 			}
 
-		case "down", "j":
+		case key.Matches(msg, k.Down):
 			m.input = ""
 			if m.cursorPosition < len(m.torrents)-1 {
 				m.cursorPosition++
 			} else {
 				m.cursorPosition = len(m.torrents) - 1
 			}
-
-		// numbers and backspace change input number
-		case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
-			m.input += msg.String()
-			inputNumber, _ := strconv.Atoi(m.input)
-			if inputNumber < len(m.torrents)-1 {
-				m.cursorPosition = inputNumber
+			if m.mode == List {
+				cmd = m.loadMoreAtEnd()
 			}
 
-		case "backspace":
-			// we can do this safely because m.input contains numbers only
-			if len(m.input) > 0 {
-				m.input = m.input[:len(m.input)-1]
-				m.cursorPosition, _ = strconv.Atoi(m.input)
+		case key.Matches(msg, k.Top):
+			m.input = ""
+			m.cursorPosition = 0
+
+		case key.Matches(msg, k.Bottom):
+			m.input = ""
+			m.cursorPosition = len(m.torrents) - 1
+			if m.mode == List {
+				cmd = m.loadMoreAtEnd()
 			}
 
-		case "s":
+		case key.Matches(msg, k.SearchS):
+			if m.mode == Bookmarks {
+				m.leaveBookmarks()
+			}
 			cmd = m.enterSearchMode()
 
-		case "d":
+		case m.mode == List && key.Matches(msg, k.Filter):
+			cmd = m.enterFilterMode()
+
+		case m.mode == List && key.Matches(msg, k.Sort):
+			m.sortField = m.sortField.next()
+			m.applyFilterAndSort()
+
+		case m.mode == List && key.Matches(msg, k.ReverseSort):
+			m.sortDescending = !m.sortDescending
+			m.applyFilterAndSort()
+
+		case m.mode == List && key.Matches(msg, k.AddBookmark):
+			cmd = m.startBookmark()
+
+		case m.mode == List && key.Matches(msg, k.ShowBookmarks):
+			m.enterBookmarks()
+
+		case m.mode == Bookmarks && key.Matches(msg, k.DeleteBookmark):
+			m.deleteBookmark()
+
+		case key.Matches(msg, k.ShowDescription):
 			m.showDescription()
 
-		case "f":
+		case key.Matches(msg, k.ShowFiles):
 			m.showFiles()
 
-		case "c":
+		case key.Matches(msg, k.CopyMagnetLink):
 			m.copyMagnetLinkToClipBoard()
 
-		case "enter":
+		case key.Matches(msg, k.GetTorrent):
 			go visitMagnetLink(m.torrents[m.cursorPosition])
 			if !m.persist {
 				return true, nil
 			}
 
-		case "t":
+		case key.Matches(msg, k.DownloadTorrent):
 			cmd = m.downloadTorrent()
 
-		case "g":
-			go m.getCurrentTorrent().Client.NavigateTo(*m.getCurrentTorrent())
+		case key.Matches(msg, k.NavigateToTorrent):
+			m.navigateToTorrent()
+
+		case m.mode == List && key.Matches(msg, k.NewTab):
+			cmd = m.newTab()
+
+		case m.mode == List && key.Matches(msg, k.CloseTab):
+			m.closeTab()
+
+		case m.mode == List && key.Matches(msg, k.NextTab):
+			m.switchTab(1)
+
+		case m.mode == List && key.Matches(msg, k.PreviousTab):
+			m.switchTab(-1)
+
+		case key.Matches(msg, k.TogglePreview):
+			m.togglePreview()
 
-		case "?":
+		case key.Matches(msg, k.SwitchPreview):
+			m.switchPreview()
+
+		case key.Matches(msg, k.Help):
 			m.toggleHelp()
+
+		default:
+			m.inputNumber(msg.String())
 		}
 
-		m.viewport.SetContent(m.GetContent())
 	case ShowDescription, ShowFiles:
-		switch keyString {
-		case "ctrl+c":
-			return true, nil
+		switch {
+		case key.Matches(msg, k.GoBackQEsc):
+			m.setMode(m.listMode)
 
-		case "q", "esc":
-			m.keys = keys.ListKeys
-			m.mode = List
-
-		case "s":
+		case key.Matches(msg, k.SearchS):
+			if m.listMode == Bookmarks {
+				m.leaveBookmarks()
+			}
 			cmd = m.enterSearchMode()
 
-		case "d":
+		case key.Matches(msg, k.AddBookmark):
+			if m.listMode == List {
+				cmd = m.startBookmark()
+			}
+
+		case key.Matches(msg, k.ShowDescription):
 			m.showDescription()
 
-		case "f":
+		case key.Matches(msg, k.ShowFiles):
 			m.showFiles()
 
-		case "c":
+		case key.Matches(msg, k.CopyMagnetLink):
 			m.copyMagnetLinkToClipBoard()
 
-		case "enter":
+		case key.Matches(msg, k.GetTorrent):
 			go visitMagnetLink(m.torrents[m.cursorPosition])
 			if !m.persist {
 				return true, nil
 			}
 
-		case "t":
+		case key.Matches(msg, k.DownloadTorrent):
 			cmd = m.downloadTorrent()
 
-		case "g":
-			go m.getCurrentTorrent().Client.NavigateTo(*m.getCurrentTorrent())
+		case key.Matches(msg, k.NavigateToTorrent):
+			m.navigateToTorrent()
 
-		case "?":
+		case key.Matches(msg, k.Help):
 			m.toggleHelp()
 
-		case "up", "k":
+		case key.Matches(msg, k.Up):
 			m.viewport.LineUp(1)
 
-		case "down", "j":
+		case key.Matches(msg, k.Down):
 			m.viewport.LineDown(1)
+
+		case key.Matches(msg, k.Top):
+			m.viewport.GotoTop()
+
+		case key.Matches(msg, k.Bottom):
+			m.viewport.GotoBottom()
+
+		case m.mode == ShowDescription && key.Matches(msg, k.NextLink):
+			m.selectLink(1)
+
+		case m.mode == ShowDescription && key.Matches(msg, k.PreviousLink):
+			m.selectLink(-1)
+
+		case m.mode == ShowDescription && key.Matches(msg, k.OpenLink):
+			cmd = m.openLink()
 		}
 	case Search:
-		switch keyString {
-		case "ctrl+c":
-			return true, nil
-
-		case "esc":
-			if len(m.torrents) > 0 {
+		switch {
+		case key.Matches(msg, k.GoBackEsc):
+			if m.reverseSearch {
+				m.stopReverseSearch(false)
+			} else if len(m.torrents) > 0 {
+				m.keys = keys.ListKeys
+				m.mode = List
+			} else if len(m.tabs) > 1 {
+				// tabs without results are closed instead of quitting
+				m.closeTab()
 				m.keys = keys.ListKeys
 				m.mode = List
 			} else {
 				return true, nil
 			}
 
-		case "enter":
-			m.cursorPosition = 0
-			m.torrents = m.client.Search(m.searchInput.Value())
+		case key.Matches(msg, k.PreviousQuery):
+			if !m.reverseSearch {
+				m.previousQuery()
+			}
+
+		case key.Matches(msg, k.NextQuery):
+			if !m.reverseSearch {
+				m.nextQuery()
+			}
+
+		case key.Matches(msg, k.ReverseSearch):
+			m.startReverseSearch()
+
+		case key.Matches(msg, k.SearchEnter):
+			if m.reverseSearch {
+				m.stopReverseSearch(true)
+			}
+			cmd, ok := m.search(m.searchInput.Value())
+			if !ok {
+				break
+			}
 			m.mode = List
 			m.keys = keys.ListKeys
+			return false, cmd
+
+		default:
+			// the input has changed, look for a new match
+			if m.reverseSearch {
+				m.updateReverseSearch(len(m.historyQueries) - 1)
+			}
+		}
+	case BookmarkNote:
+		switch {
+		case key.Matches(msg, k.GoBackEsc):
+			m.finishBookmark(false)
+
+		case key.Matches(msg, k.SaveBookmark):
+			m.finishBookmark(true)
+		}
+	case Filter:
+		switch {
+		case key.Matches(msg, k.GoBackEsc):
+			m.filterInput.Blur()
+			m.keys = keys.ListKeys
+			m.mode = List
+
+		case key.Matches(msg, k.FilterEnter):
+			f, err := filter.Parse(m.filterInput.Value())
+			if err != nil {
+				m.errorMessage = err.Error()
+				break
+			}
+			m.filter = f
+			m.applyFilterAndSort()
+			m.filterInput.Blur()
+			m.keys = keys.ListKeys
+			m.mode = List
 		}
 	}
 	return false, cmd
 }
 
+// inputNumber handles the keys used to type a torrent's number, which moves
+// the cursor to it
+func (m *Model) inputNumber(keyString string) {
+	switch keyString {
+	case "0", "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		m.input += keyString
+		inputNumber, _ := strconv.Atoi(m.input)
+		if inputNumber < len(m.torrents)-1 {
+			m.cursorPosition = inputNumber
+		}
+
+	case "backspace":
+		// we can do this safely because m.input contains numbers only
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+			m.cursorPosition, _ = strconv.Atoi(m.input)
+		}
+	}
+}
+
+// keySequence handles keys bound as a sequence, such as "g t". It returns
+// true while the first key of a sequence waits for the next one, and
+// returns the whole sequence as a single key once it's complete.
+func (m *Model) keySequence(msg tea.KeyMsg) (tea.KeyMsg, bool) {
+	if m.mode != List && m.mode != Bookmarks && m.mode != ShowDescription && m.mode != ShowFiles {
+		m.pendingKey = ""
+		return msg, false
+	}
+	if m.pendingKey != "" {
+		sequence := m.pendingKey + " " + msg.String()
+		m.pendingKey = ""
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sequence)}, false
+	}
+	if keys.IsPrefix(msg.String()) {
+		m.pendingKey = msg.String()
+		return msg, true
+	}
+	return msg, false
+}
+
+// needsTorrent returns true if the key acts on the torrent under the cursor
+func needsTorrent(msg tea.KeyMsg) bool {
+	k := keys.All
+	return key.Matches(msg, k.ShowDescription, k.ShowFiles, k.CopyMagnetLink, k.GetTorrent,
+		k.DownloadTorrent, k.NavigateToTorrent, k.AddBookmark, k.DeleteBookmark)
+}
+
 func (m *Model) headerView() string {
 	var title string
+	k := keys.All
 	switch m.mode {
 	case List:
-		title = "Select torrent to get, or input number and press enter\n"
+		title = "Select torrent to get, or input number and press " + k.GetTorrent.Help().Key
 	case ShowDescription:
-		title = m.getCurrentTorrent().Title + "\n"
+		title = m.getCurrentTorrent().Title
 	case ShowFiles:
-		title = m.getCurrentTorrent().Title + " files\n"
+		title = m.getCurrentTorrent().Title + " files"
 	case Search:
-		title = "Enter query and press enter to search, or press esc to go back\n"
+		title = fmt.Sprintf("Enter query and press %s to search, or press %s to go back", k.SearchEnter.Help().Key, k.GoBackEsc.Help().Key)
+	case Filter:
+		title = fmt.Sprintf("Enter filter and press %s to apply, or press %s to go back", k.FilterEnter.Help().Key, k.GoBackEsc.Help().Key)
+	case Bookmarks:
+		title = fmt.Sprintf("Bookmarks, press %s to go back to the search results", k.GoBackQEsc.Help().Key)
+	case BookmarkNote:
+		title = fmt.Sprintf("Enter a note for %s and press %s to save the bookmark, or press %s to cancel",
+			m.getCurrentTorrent().Title, k.SaveBookmark.Help().Key, k.GoBackEsc.Help().Key)
 	}
 
-	return title
+	if tabBar := m.tabBar(); tabBar != "" {
+		return tabBar + "\n" + m.theme.Header.Render(title) + "\n"
+	}
+	return m.theme.Header.Render(title) + "\n"
 }
 
 func (m *Model) footerView() string {
-	info := "\nInput torrent number: "
-	info += selectedStyle.Render(m.input) + "\n"
-	info += m.message + "\n"
+	info := "\n" + m.theme.Footer.Render("Input torrent number: ")
+	info += m.theme.Input.Render(m.input) + "\n"
+	if m.errorMessage != "" {
+		info += m.theme.Error.Render(m.errorMessage) + "\n"
+	} else {
+		info += m.message + "\n"
+	}
+	info += m.theme.Footer.Render(m.resultsInfo()) + "\n"
 
 	helpView := m.help.View(m.keys)
 
@@ -287,58 +533,128 @@ func (m *Model) footerView() string {
 func (m *Model) GetContent() string {
 	switch m.mode {
 	case ShowDescription:
-		return m.getCurrentTorrent().Description
+		return m.GetDescriptionContent()
 	case ShowFiles:
 		return m.GetTorrentFilesTable()
 	case Search:
 		return m.GetSearchContent()
+	case Filter:
+		return m.filterInput.View() + "\n" + m.GetTorrentsTable()
+	case Bookmarks:
+		return m.GetBookmarksTable()
+	case BookmarkNote:
+		return m.noteInput.View()
 	default:
 		return m.GetTorrentsTable()
 	}
 }
 
+// GetDescriptionContent returns the torrent's description, preceded by
+// information about its uploader and any warnings about it
+func (m *Model) GetDescriptionContent() string {
+	s := m.describe(*m.getCurrentTorrent(), m.viewport.Width)
+	if len(m.links) == 0 {
+		return s
+	}
+
+	s += "\n\n" + m.theme.Header.Render("Links") + "\n"
+	for i, link := range m.links {
+		cursor := " "
+		if m.linkCursor == i {
+			cursor = ">"
+		}
+		row := fmt.Sprintf("%s [%d] %s", cursor, i+1, link.Text)
+		if link.Text != link.URL {
+			row += " " + link.URL
+		}
+		if m.viewport.Width > 0 {
+			row = runewidth.Truncate(row, m.viewport.Width, ellipsis)
+		}
+		if m.linkCursor == i {
+			row = m.theme.Selected.Render(row)
+		}
+		s += row + "\n"
+	}
+	return s
+}
+
+// markupStyles are the styles of links and headings in descriptions
+func (m *Model) markupStyles() markup.Styles {
+	return markup.Styles{
+		Link:    m.theme.Input,
+		Heading: m.theme.Header,
+		Note:    m.theme.Footer,
+	}
+}
+
+// describe returns a torrent's description wrapped to width, preceded by information about
+// its uploader and any warnings about it
+func (m *Model) describe(t interfaces.Torrent, width int) string {
+	s := ""
+	if b, ok := m.currentBookmark(); ok && b.Note != "" {
+		s += "Note: " + b.Note + "\n"
+	}
+	if t.Uploader != "" {
+		s += "Uploaded by " + t.Uploader
+		if t.UploaderStatus != "" {
+			s += " (" + t.UploaderStatus + ")"
+		}
+		s += "\n"
+	} else if t.UploaderStatus != "" {
+		s += "Uploader status: " + t.UploaderStatus + "\n"
+	}
+	if details := movieDetails(t); details != "" {
+		s += details + "\n"
+	}
+	s += m.assessor.Assess(t).Explain(keys.All.ShowFiles.Help().Key) + "\n"
+
+	return s + markup.Render(t.Description, width, m.markupStyles()).Text
+}
+
+// movieDetails returns the IMDb id, runtime and genres of the torrent, if its
+// provider knows them
+func movieDetails(t interfaces.Torrent) string {
+	var details []string
+	if t.IMDbID != "" {
+		details = append(details, "IMDb: "+t.IMDbID)
+	}
+	if t.Runtime > 0 {
+		details = append(details, fmt.Sprintf("Runtime: %dh%02dm", t.Runtime/60, t.Runtime%60))
+	}
+	if len(t.Genres) > 0 {
+		details = append(details, "Genres: "+strings.Join(t.Genres, ", "))
+	}
+	return strings.Join(details, " | ")
+}
+
 func (m *Model) GetSearchContent() string {
+	if m.reverseSearch {
+		return m.searchInput.View() + "\n" + m.reverseSearchMatch()
+	}
 	return m.searchInput.View()
 }
 
 func (m *Model) GetTorrentFilesTable() string {
-	nameLength := getMaxFileNameLength(m.getCurrentTorrent().Files)
+	return filesTable(m.getCurrentTorrent().Files)
+}
+
+func filesTable(files []interfaces.TorrentFile) string {
+	nameLength := getMaxFileNameLength(files)
 	nameLenghtAsString := strconv.Itoa(nameLength)
 
 	// table header
 	// the Name column with is variable, it is as wide as the longest name
-	s := fmt.Sprintf("%3s %"+nameLenghtAsString+"s %9s\n", "No.", "Name", "Size")
+	s := fmt.Sprintf("%3s %-"+nameLenghtAsString+"s %9s\n", "No.", "Name", "Size")
 
 	// Iterate over our choices
-	for i, file := range m.getCurrentTorrent().Files {
-		s += fmt.Sprintf("%3d %"+nameLenghtAsString+"s %9s\n", i, file.Name, file.GetPrettySize())
+	for i, file := range files {
+		s += fmt.Sprintf("%3d %-"+nameLenghtAsString+"s %9s\n", i, file.Name, file.GetPrettySize())
 	}
 	return s
 }
 
 func (m *Model) GetTorrentsTable() string {
-	// table header
-	s := fmt.Sprintf("%s %3s %64s %9s %4s %4s %s\n", " ", "No.", "Title", "Size", "S", "L", "Uploaded")
-
-	for i, torrent := range m.torrents {
-		dateInt, err := strconv.ParseInt(torrent.Uploaded, 10, 64)
-		var date string
-		if err != nil {
-			date = "err"
-		} else {
-			date = time.Unix(dateInt, 0).Format("2006-01-02")
-		}
-
-		// Is the cursor pointing at this choice?
-		cursor := " "
-		if m.cursorPosition == i {
-			cursor = ">"
-			s += selectedStyle.Render(fmt.Sprintf("%s %3d %64s %9s %4d %4d %s", cursor, i, torrent.Title, torrent.GetPrettySize(), torrent.Seeders, torrent.Leechers, date)) + "\n"
-		} else {
-			s += fmt.Sprintf("%s %3d %64s %9s %4d %4d %s\n", cursor, i, torrent.Title, torrent.GetPrettySize(), torrent.Seeders, torrent.Leechers, date)
-		}
-	}
-	return s
+	return m.renderTable(m.columns, m.torrents)
 }
 
 func getMaxFileNameLength(torrentFiles []interfaces.TorrentFile) int {
@@ -356,13 +672,13 @@ func (m Model) View() string {
 	if !m.ready {
 		return "\n  Initializing..."
 	}
-	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.viewport.View(), m.footerView())
+	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.contentView(), m.footerView())
 }
 
 func (m *Model) copyMagnetLinkToClipBoard() {
 	torrent := m.torrents[m.cursorPosition]
 	if err := clipboard.WriteAll(torrent.MagnetLink); err != nil {
-		m.message = "Error while copying magnet link to clipboard"
+		m.errorMessage = "Error while copying magnet link to clipboard"
 	} else {
 		m.message = "Magnet link copied to clipboard"
 	}
@@ -370,21 +686,14 @@ func (m *Model) copyMagnetLinkToClipBoard() {
 
 func cmdDownloadTorrentFile(m Model) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("http://itorrents.org/torrent/%s.torrent", m.getCurrentTorrent().InfoHash)
-		result, err := http.Get(url)
+		cache := torrentcache.New(torrentcache.DefaultDir(), clients.HTTPClient())
+		data, err := cache.Get(m.getCurrentTorrent().InfoHash)
 		if err != nil {
 			return errMsg{err}
 		}
-		defer result.Body.Close()
 
 		fileName := fmt.Sprintf("%s%s.torrent", m.downloadLocation, m.getCurrentTorrent().Title)
-		file, err := os.Create(fileName)
-		if err != nil {
-			return errMsg{err}
-		}
-		defer file.Close()
-		_, err = io.Copy(file, result.Body)
-		if err != nil {
+		if err := os.WriteFile(fileName, data, 0o644); err != nil {
 			return errMsg{err}
 		}
 		return statusMsg{"Downloaded file: " + fileName}
@@ -404,6 +713,7 @@ func (m *Model) getCurrentTorrent() *interfaces.Torrent {
 
 func (m *Model) enterSearchMode() tea.Cmd {
 	m.searchInput.SetValue("")
+	m.loadHistory()
 	cmd := m.searchInput.Focus()
 	m.keys = keys.SearchKeys
 	m.mode = Search
@@ -411,35 +721,153 @@ func (m *Model) enterSearchMode() tea.Cmd {
 	return cmd
 }
 
+func (m *Model) enterFilterMode() tea.Cmd {
+	cmd := m.filterInput.Focus()
+	m.keys = keys.FilterKeys
+	m.mode = Filter
+
+	return cmd
+}
+
+// setResults replaces the search results and resets the cursor
+func (m *Model) setResults(torrents []interfaces.Torrent) {
+	m.results = torrents
+	m.cursorPosition = 0
+	m.applyFilterAndSort()
+}
+
+// applyFilterAndSort rebuilds the list of visible torrents from the search
+// results, using the current filter and sort order
+func (m *Model) applyFilterAndSort() {
+	m.torrents = filterAndSort(m.results, m.filter, m.sortField, m.sortDescending)
+
+	if m.cursorPosition > len(m.torrents)-1 {
+		m.cursorPosition = len(m.torrents) - 1
+	}
+	if m.cursorPosition < 0 {
+		m.cursorPosition = 0
+	}
+}
+
+// filterAndSort returns the results kept by the filter, in the given order
+func filterAndSort(results []interfaces.Torrent, f filter.Filter, field sortField, descending bool) []interfaces.Torrent {
+	filtered := f.Apply(results)
+	torrents := make([]interfaces.Torrent, len(filtered))
+	copy(torrents, filtered)
+	sortTorrents(torrents, field, descending)
+	return torrents
+}
+
+// resultsInfo describes the number of visible torrents and how they are
+// filtered and sorted
+func (m *Model) resultsInfo() string {
+	if m.listMode == Bookmarks {
+		return fmt.Sprintf("%d bookmarks", len(m.torrents))
+	}
+	info := fmt.Sprintf("%d/%d torrents", len(m.torrents), len(m.results))
+	switch {
+	case m.searching:
+		info += ", searching..."
+	case m.loadingMore:
+		info += ", loading more..."
+	case m.nextPage != "":
+		info += ", more available"
+	}
+	if !m.filter.Empty() {
+		info += ", filter: " + m.filterInput.Value()
+	}
+	if m.sortField != sortNone {
+		order := "ascending"
+		if m.sortDescending {
+			order = "descending"
+		}
+		info += fmt.Sprintf(", sorted by %s (%s)", m.sortField, order)
+	}
+	return info
+}
+
 func (m *Model) showDescription() {
 	t := m.getCurrentTorrent()
+	if t.Client == nil {
+		m.message = "This torrent's provider is not available"
+		return
+	}
 	if t.Description == "" {
-		t.Description = t.FetchDescription()
+		description, ok := m.cachedDescription(*t)
+		if !ok {
+			description = t.FetchDescription()
+			// the visible torrents are copies, rebuilt from the results
+			// when they're filtered or sorted
+			m.previewCache["description:"+t.Key()] = previewEntry{description: description}
+		}
+		t.Description = description
 	}
+	m.links = markup.Render(t.Description, 0, m.markupStyles()).Links
+	m.linkCursor = 0
 	m.keys = keys.DescriptionKeys
 	m.mode = ShowDescription
 }
 
 func (m *Model) showFiles() {
 	t := m.getCurrentTorrent()
+	if t.Client == nil {
+		m.message = "This torrent's provider is not available"
+		return
+	}
 	if t.Files == nil {
-		t.Files = t.FetchFiles()
+		files, ok := m.cachedFiles(*t)
+		if !ok {
+			files = t.FetchFiles()
+			m.previewCache["files:"+t.Key()] = previewEntry{files: files}
+		}
+		t.Files = files
 	}
 	m.keys = keys.FilesKeys
 	m.mode = ShowFiles
 }
 
+// selectLink moves the cursor in the description's links, and scrolls to
+// them since they're listed at the end
+func (m *Model) selectLink(step int) {
+	if len(m.links) == 0 {
+		return
+	}
+	m.linkCursor = (m.linkCursor + step + len(m.links)) % len(m.links)
+	m.viewport.GotoBottom()
+}
+
+func (m *Model) openLink() tea.Cmd {
+	if m.linkCursor >= len(m.links) {
+		return nil
+	}
+	url := m.links[m.linkCursor].URL
+	if !httpclient.WebLink(url) {
+		m.message = "Only http and https links can be opened"
+		return nil
+	}
+	return func() tea.Msg {
+		if err := open.Run(url); err != nil {
+			return errMsg{err}
+		}
+		return statusMsg{"Opened " + url}
+	}
+}
+
+func (m *Model) navigateToTorrent() {
+	t := m.getCurrentTorrent()
+	if t.Client == nil {
+		m.message = "This torrent's provider is not available"
+		return
+	}
+	go t.Client.NavigateTo(*t)
+}
+
 func (m *Model) downloadTorrent() tea.Cmd {
 	m.message = "Downloading .torrent"
 	return cmdDownloadTorrentFile(*m)
 }
 
 func (m *Model) toggleHelp() {
+	// the viewport is adjusted to the footer's new size by layout
 	m.help.ShowAll = !m.help.ShowAll
-
-	// adjust viewport, since toggling help changes footer size
-	headerHeight := lipgloss.Height(m.headerView())
-	footerHeight := lipgloss.Height(m.footerView())
-	verticalMarginHeight := headerHeight + footerHeight
-	m.viewport.Height = m.height - verticalMarginHeight
 }
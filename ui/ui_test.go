@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+	"github.com/ismaelpadilla/gotorrent/ui/keys"
+)
+
+// remapKeys changes keys for the rest of the test
+func remapKeys(t *testing.T, remap map[string][]string) {
+	t.Helper()
+	saved := keys.All
+	if err := keys.Configure("default", remap); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		keys.All = saved
+		_ = keys.Configure("default", nil)
+	})
+}
+
+func TestHeaderShowsRemappedKeys(t *testing.T) {
+	remapKeys(t, map[string][]string{
+		"get-torrent": {"ctrl+o"},
+		"confirm":     {"ctrl+s"},
+		"cancel":      {"ctrl+g"},
+		"go-back":     {"ctrl+b"},
+	})
+	m := newTestModel()
+	m.torrents = []interfaces.Torrent{{Title: "ubuntu"}}
+
+	tests := map[Mode][]string{
+		List:         {"press ctrl+o"},
+		Search:       {"press ctrl+s to search", "press ctrl+g to go back"},
+		Filter:       {"press ctrl+s to apply", "press ctrl+g to go back"},
+		Bookmarks:    {"press ctrl+b to go back"},
+		BookmarkNote: {"press ctrl+s to save", "press ctrl+g to cancel"},
+	}
+	for mode, want := range tests {
+		m.mode = mode
+		header := m.headerView()
+		for _, w := range want {
+			if !strings.Contains(header, w) {
+				t.Errorf("header in mode %d = %q, want %q", mode, header, w)
+			}
+		}
+		if strings.Contains(header, "enter") || strings.Contains(header, "esc") {
+			t.Errorf("header in mode %d = %q, has the default keys", mode, header)
+		}
+	}
+}
+
+func TestDescriptionShowsRemappedFilesKey(t *testing.T) {
+	remapKeys(t, map[string][]string{"show-files": {"F"}})
+	m := newTestModel()
+
+	if s := m.describe(interfaces.Torrent{Title: "ubuntu"}, 80); !strings.Contains(s, "press F to fetch") {
+		t.Errorf("description = %q, want the remapped key", s)
+	}
+}
@@ -0,0 +1,110 @@
+package watch
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ismaelpadilla/gotorrent/downloader"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// Action is run for every new torrent found by a saved search.
+type Action interface {
+	Run(search SavedSearch, torrent interfaces.Torrent) error
+}
+
+// Actions maps action names, as used in saved searches, to actions.
+type Actions map[string]Action
+
+// ActionsConfig configures the built-in actions.
+type ActionsConfig struct {
+	Logger *log.Logger
+	// NotifyCommand is run by the notify action. {title}, {magnet}, {hash}
+	// and {search} are replaced by the torrent's values.
+	NotifyCommand string
+	Downloader    downloader.Downloader
+	// Folder is where the folder action writes .magnet files
+	Folder string
+}
+
+const defaultNotifyCommand = "notify-send gotorrent {title}"
+
+// NewActions returns the built-in actions: log, notify, send and folder.
+func NewActions(config ActionsConfig) Actions {
+	notifyCommand := config.NotifyCommand
+	if notifyCommand == "" {
+		notifyCommand = defaultNotifyCommand
+	}
+
+	return Actions{
+		"log":    logAction{config.Logger},
+		"notify": commandAction{notifyCommand},
+		"send":   sendAction{config.Downloader},
+		"folder": folderAction{config.Folder},
+	}
+}
+
+type logAction struct {
+	logger *log.Logger
+}
+
+func (a logAction) Run(search SavedSearch, t interfaces.Torrent) error {
+	a.logger.Printf("[%s] new torrent: %s (%s, %d seeders) %s", search.Name, t.Title, t.GetPrettySize(), t.Seeders, t.MagnetLink)
+	return nil
+}
+
+// commandAction runs an external command, such as a desktop notification
+type commandAction struct {
+	command string
+}
+
+func (a commandAction) Run(search SavedSearch, t interfaces.Torrent) error {
+	replacer := strings.NewReplacer(
+		"{title}", t.Title,
+		"{magnet}", t.MagnetLink,
+		"{hash}", t.InfoHash,
+		"{search}", search.Name,
+	)
+
+	fields := strings.Fields(a.command)
+	args := make([]string, len(fields))
+	for i, f := range fields {
+		args[i] = replacer.Replace(f)
+	}
+
+	// #nosec G204 -- the command comes from the user's configuration
+	output, err := exec.Command(args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+type sendAction struct {
+	downloader downloader.Downloader
+}
+
+func (a sendAction) Run(_ SavedSearch, t interfaces.Torrent) error {
+	return a.downloader.Send(t.MagnetLink)
+}
+
+// folderAction writes a .magnet file to a folder watched by a torrent client
+type folderAction struct {
+	folder string
+}
+
+func (a folderAction) Run(_ SavedSearch, t interfaces.Torrent) error {
+	if a.folder == "" {
+		return fmt.Errorf("no watch folder configured")
+	}
+	if err := os.MkdirAll(a.folder, 0o755); err != nil {
+		return err
+	}
+
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(t.Title) + ".magnet"
+	return os.WriteFile(filepath.Join(a.folder, name), []byte(t.MagnetLink+"\n"), 0o600)
+}
@@ -0,0 +1,127 @@
+package watch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ismaelpadilla/gotorrent/filter"
+	"github.com/ismaelpadilla/gotorrent/xdg"
+)
+
+// SavedSearch is a query that is periodically run by the watcher.
+type SavedSearch struct {
+	Name     string `json:"name"`
+	Query    string `json:"query"`
+	Provider string `json:"provider"`
+	// Filter is a filter expression applied to the results, see the filter
+	// package
+	Filter string `json:"filter,omitempty"`
+	// Actions are run for every new torrent, see Actions
+	Actions []string `json:"actions"`
+}
+
+// Validate checks that the saved search can be run.
+func (s SavedSearch) Validate(actions Actions) error {
+	if s.Name == "" {
+		return errors.New("saved search needs a name")
+	}
+	if s.Query == "" {
+		return errors.New("saved search needs a query")
+	}
+	if _, err := filter.Parse(s.Filter); err != nil {
+		return err
+	}
+	for _, a := range s.Actions {
+		if _, ok := actions[a]; !ok {
+			return fmt.Errorf("unknown action %q", a)
+		}
+	}
+	return nil
+}
+
+// SearchStore keeps saved searches in a JSON file.
+type SearchStore struct {
+	path string
+}
+
+// DefaultSearchesPath returns the location of the saved searches file.
+func DefaultSearchesPath() string {
+	return filepath.Join(xdg.DataDir(), "saved-searches.json")
+}
+
+func NewSearchStore(path string) *SearchStore {
+	return &SearchStore{path: path}
+}
+
+// List returns every saved search. A missing file is not an error.
+func (s *SearchStore) List() ([]SavedSearch, error) {
+	var searches []SavedSearch
+	if err := readJSON(s.path, &searches); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// Add saves a search, replacing any search with the same name.
+func (s *SearchStore) Add(search SavedSearch) error {
+	searches, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range searches {
+		if searches[i].Name == search.Name {
+			searches[i] = search
+			replaced = true
+		}
+	}
+	if !replaced {
+		searches = append(searches, search)
+	}
+	return writeJSON(s.path, searches)
+}
+
+// Remove deletes the saved search with the given name.
+func (s *SearchStore) Remove(name string) error {
+	searches, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	kept := searches[:0]
+	for _, search := range searches {
+		if search.Name != name {
+			kept = append(kept, search)
+		}
+	}
+	if len(kept) == len(searches) {
+		return fmt.Errorf("no saved search named %q", name)
+	}
+	return writeJSON(s.path, kept)
+}
+
+// readJSON decodes the file at path into v, leaving v untouched if the file
+// doesn't exist
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSON atomically replaces the file at path with v encoded as JSON
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return xdg.WriteFile(path, data)
+}
@@ -0,0 +1,82 @@
+package watch
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/xdg"
+)
+
+// State remembers which torrents have already been seen by each saved search,
+// so they are not acted on again after a restart.
+type State struct {
+	path string
+	// torrents seen by each saved search, by their key, and when they were
+	// first seen
+	Seen map[string]map[string]time.Time `json:"seen"`
+}
+
+// DefaultStatePath returns the location of the watcher's state file.
+func DefaultStatePath() string {
+	return filepath.Join(xdg.StateDir(), "watch.json")
+}
+
+// LoadState reads the state from path. A missing file results in an empty
+// state.
+func LoadState(path string) (*State, error) {
+	s := &State{path: path}
+	if err := readJSON(path, s); err != nil {
+		return nil, err
+	}
+	if s.Seen == nil {
+		s.Seen = make(map[string]map[string]time.Time)
+	}
+	return s, nil
+}
+
+// Save writes the state to disk.
+func (s *State) Save() error {
+	return writeJSON(s.path, s)
+}
+
+// Known returns true if the saved search has been run before.
+func (s *State) Known(search string) bool {
+	_, ok := s.Seen[search]
+	return ok
+}
+
+// Track starts recording torrents for a saved search, so it is known even if
+// it had no results.
+func (s *State) Track(search string) {
+	if _, ok := s.Seen[search]; !ok {
+		s.Seen[search] = make(map[string]time.Time)
+	}
+}
+
+// IsSeen returns true if a torrent, by its key, has been seen by a saved
+// search.
+func (s *State) IsSeen(search string, key string) bool {
+	_, ok := s.Seen[search][key]
+	return ok
+}
+
+// MarkSeen records a torrent, by its key, for a saved search. It returns
+// false if the torrent had already been seen.
+func (s *State) MarkSeen(search string, key string, now time.Time) bool {
+	seen, ok := s.Seen[search]
+	if !ok {
+		seen = make(map[string]time.Time)
+		s.Seen[search] = seen
+	}
+
+	if _, ok := seen[key]; ok {
+		return false
+	}
+	seen[key] = now
+	return true
+}
+
+// Forget removes everything seen by a saved search.
+func (s *State) Forget(search string) {
+	delete(s.Seen, search)
+}
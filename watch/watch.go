@@ -0,0 +1,134 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/clients"
+	"github.com/ismaelpadilla/gotorrent/filter"
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+// Clock abstracts time so the watcher can be driven by a fake clock.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RealClock returns a clock backed by the time package.
+func RealClock() Clock {
+	return realClock{}
+}
+
+// Watcher periodically runs saved searches and acts on torrents that haven't
+// been seen before.
+type Watcher struct {
+	Searches []SavedSearch
+	// Client returns the client for a provider
+	Client  func(provider string) (interfaces.Client, error)
+	State   *State
+	Actions Actions
+	Clock   Clock
+	Logger  *log.Logger
+	// Interval is the time between runs, it must be positive
+	Interval time.Duration
+	// NotifyExisting makes the first run of a saved search act on every
+	// result. Otherwise existing results are only recorded as seen.
+	NotifyExisting bool
+}
+
+// Run runs every saved search each Interval until the context is cancelled.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.Interval <= 0 {
+		return errors.New("the interval between runs must be positive")
+	}
+	for {
+		w.RunOnce()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.Clock.After(w.Interval):
+		}
+	}
+}
+
+// RunOnce runs every saved search once, and saves the state.
+func (w *Watcher) RunOnce() {
+	for _, s := range w.Searches {
+		if err := w.runSearch(s); err != nil {
+			w.Logger.Printf("[%s] %v", s.Name, err)
+		}
+	}
+
+	if err := w.State.Save(); err != nil {
+		w.Logger.Printf("error while saving state: %v", err)
+	}
+}
+
+func (w *Watcher) runSearch(s SavedSearch) error {
+	f, err := filter.Parse(s.Filter)
+	if err != nil {
+		return err
+	}
+	client, err := w.Client(s.Provider)
+	if err != nil {
+		return err
+	}
+
+	// clients panic on network errors, which shouldn't stop the watcher
+	var found []interfaces.Torrent
+	if err := clients.Recover(func() { found = client.Search(s.Query) }); err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	torrents := f.Apply(found)
+
+	firstRun := !w.State.Known(s.Name)
+	w.State.Track(s.Name)
+
+	for _, t := range torrents {
+		key := t.Key()
+		if w.State.IsSeen(s.Name, key) {
+			continue
+		}
+		// torrents are only seen once their actions succeed, so failed
+		// ones are tried again in the next run
+		if (!firstRun || w.NotifyExisting) && !w.runActions(s, t) {
+			continue
+		}
+		w.State.MarkSeen(s.Name, key, w.Clock.Now())
+	}
+	return nil
+}
+
+// runActions runs the actions of a saved search for a torrent, and returns
+// false if any of them failed
+func (w *Watcher) runActions(s SavedSearch, t interfaces.Torrent) bool {
+	ok := true
+	for _, name := range s.Actions {
+		action, found := w.Actions[name]
+		if !found {
+			// running it again wouldn't help
+			w.Logger.Printf("[%s] unknown action %q", s.Name, name)
+			continue
+		}
+		if err := action.Run(s, t); err != nil {
+			w.Logger.Printf("[%s] action %s failed for %s: %v", s.Name, name, t.Title, err)
+			ok = false
+		}
+	}
+	return ok
+}
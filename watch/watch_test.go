@@ -0,0 +1,259 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ismaelpadilla/gotorrent/interfaces"
+)
+
+type fakeClock struct {
+	now    time.Time
+	waits  []time.Duration
+	cancel context.CancelFunc
+	// runs is how many times Run waits before the context is cancelled
+	runs int
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	if len(c.waits) >= c.runs {
+		c.cancel()
+		return nil
+	}
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// fakeClient returns its results for every search, or panics if they're nil
+type fakeClient struct {
+	results  []interfaces.Torrent
+	searches int
+}
+
+func (c *fakeClient) Name() string { return "fake" }
+
+func (c *fakeClient) Search(query string) []interfaces.Torrent {
+	c.searches++
+	if c.results == nil {
+		panic("network error")
+	}
+	for i := range c.results {
+		c.results[i].Client = c
+	}
+	return c.results
+}
+
+func (c *fakeClient) SearchPage(query string, page string) ([]interfaces.Torrent, string) {
+	return c.Search(query), ""
+}
+
+func (c *fakeClient) NavigateTo(interfaces.Torrent) {}
+
+func (c *fakeClient) FetchTorrentDescription(interfaces.Torrent) string { return "" }
+
+func (c *fakeClient) FetchTorrentFiles(interfaces.Torrent) []interfaces.TorrentFile { return nil }
+
+// recordAction records the titles of the torrents it runs for, and fails
+// while fail is set
+type recordAction struct {
+	titles []string
+	fail   bool
+}
+
+func (a *recordAction) Run(_ SavedSearch, t interfaces.Torrent) error {
+	if a.fail {
+		return errors.New("torrent client not running")
+	}
+	a.titles = append(a.titles, t.Title)
+	return nil
+}
+
+type fixture struct {
+	watcher *Watcher
+	client  *fakeClient
+	action  *recordAction
+	clock   *fakeClock
+	logs    *bytes.Buffer
+}
+
+func newFixture(t *testing.T, results ...interfaces.Torrent) *fixture {
+	state, err := LoadState(filepath.Join(t.TempDir(), "watch.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fixture{
+		client: &fakeClient{results: results},
+		action: &recordAction{},
+		clock:  &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		logs:   &bytes.Buffer{},
+	}
+	f.watcher = &Watcher{
+		Searches: []SavedSearch{{Name: "ubuntu", Query: "ubuntu", Provider: "fake", Actions: []string{"record"}}},
+		Client: func(provider string) (interfaces.Client, error) {
+			return f.client, nil
+		},
+		State:    state,
+		Actions:  Actions{"record": f.action},
+		Clock:    f.clock,
+		Logger:   log.New(f.logs, "", 0),
+		Interval: time.Hour,
+	}
+	return f
+}
+
+func torrent(title string, infoHash string) interfaces.Torrent {
+	return interfaces.Torrent{ID: title, Title: title, InfoHash: infoHash}
+}
+
+func (f *fixture) checkActedOn(t *testing.T, titles ...string) {
+	t.Helper()
+	if strings.Join(f.action.titles, ",") != strings.Join(titles, ",") {
+		t.Errorf("acted on %q, want %q", f.action.titles, titles)
+	}
+	f.action.titles = nil
+}
+
+func TestFirstRunRecordsExistingResults(t *testing.T) {
+	f := newFixture(t, torrent("a", "AAAA"), torrent("b", "BBBB"))
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t)
+
+	f.client.results = append(f.client.results, torrent("c", "CCCC"))
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "c")
+}
+
+func TestNotifyExisting(t *testing.T) {
+	f := newFixture(t, torrent("a", "AAAA"), torrent("b", "BBBB"))
+	f.watcher.NotifyExisting = true
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "a", "b")
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t)
+}
+
+func TestDedupAcrossRuns(t *testing.T) {
+	f := newFixture(t, torrent("a", "AAAA"))
+	f.watcher.NotifyExisting = true
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "a")
+
+	// the same torrent from another search, with a different case
+	f.client.results = []interfaces.Torrent{torrent("a again", "aaaa"), torrent("b", "BBBB")}
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "b")
+
+	// after a restart
+	state, err := LoadState(f.watcher.State.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.watcher.State = state
+	f.client.results = append(f.client.results, torrent("c", "CCCC"))
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "c")
+}
+
+func TestTorrentsWithoutInfoHash(t *testing.T) {
+	f := newFixture(t, torrent("a", ""), torrent("b", ""))
+	f.watcher.NotifyExisting = true
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "a", "b")
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t)
+}
+
+func TestFailingActionsAreRetried(t *testing.T) {
+	f := newFixture(t, torrent("a", "AAAA"))
+	f.watcher.NotifyExisting = true
+	f.action.fail = true
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t)
+	if !strings.Contains(f.logs.String(), "action record failed for a") {
+		t.Errorf("logs = %q, want the failure", f.logs.String())
+	}
+
+	f.action.fail = false
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "a")
+
+	f.watcher.RunOnce()
+	f.checkActedOn(t)
+}
+
+func TestFailingActionsOnFirstRunAreRetried(t *testing.T) {
+	f := newFixture(t, torrent("a", "AAAA"))
+	f.watcher.NotifyExisting = true
+	f.action.fail = true
+	f.watcher.RunOnce()
+
+	// existing results the first run failed to act on aren't forgotten,
+	// even if later runs aren't first runs
+	f.watcher.NotifyExisting = false
+	f.action.fail = false
+	f.watcher.RunOnce()
+	f.checkActedOn(t, "a")
+}
+
+func TestFailingSearch(t *testing.T) {
+	f := newFixture(t)
+
+	f.watcher.RunOnce()
+	if !strings.Contains(f.logs.String(), "search failed: network error") {
+		t.Errorf("logs = %q, want the failure", f.logs.String())
+	}
+	if f.watcher.State.Known("ubuntu") {
+		t.Error("a failed search was recorded as run")
+	}
+}
+
+func TestRun(t *testing.T) {
+	f := newFixture(t, torrent("a", "AAAA"))
+	ctx, cancel := context.WithCancel(context.Background())
+	f.clock.cancel = cancel
+	f.clock.runs = 3
+
+	if err := f.watcher.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if f.client.searches != 3 {
+		t.Errorf("searched %d times, want 3", f.client.searches)
+	}
+	for _, d := range f.clock.waits {
+		if d != time.Hour {
+			t.Errorf("waited %s between runs, want 1h", d)
+		}
+	}
+}
+
+func TestRunRejectsInvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		f := newFixture(t)
+		f.watcher.Interval = interval
+		if err := f.watcher.Run(context.Background()); err == nil {
+			t.Errorf("Run with interval %s returned no error", interval)
+		}
+		if f.client.searches != 0 {
+			t.Errorf("interval %s: searched %d times, want none", interval, f.client.searches)
+		}
+	}
+}
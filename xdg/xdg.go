@@ -0,0 +1,58 @@
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appName = "gotorrent"
+
+// StateDir returns the directory where gotorrent keeps state that should
+// persist between runs, such as the search history.
+// It is $XDG_STATE_HOME/gotorrent, or ~/.local/state/gotorrent if
+// $XDG_STATE_HOME is not set.
+func StateDir() string {
+	return dir("XDG_STATE_HOME", ".local/state")
+}
+
+// DataDir returns the directory where gotorrent keeps user data, such as
+// bookmarks.
+// It is $XDG_DATA_HOME/gotorrent, or ~/.local/share/gotorrent if
+// $XDG_DATA_HOME is not set.
+func DataDir() string {
+	return dir("XDG_DATA_HOME", ".local/share")
+}
+
+// CacheDir returns the directory where gotorrent keeps files that can be
+// downloaded again, such as .torrent files.
+// It is $XDG_CACHE_HOME/gotorrent, or ~/.cache/gotorrent if $XDG_CACHE_HOME
+// is not set.
+func CacheDir() string {
+	return dir("XDG_CACHE_HOME", ".cache")
+}
+
+func dir(env string, fallback string) string {
+	base := os.Getenv(env)
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, appName)
+}
+
+// WriteFile replaces the file at path with data, creating its directory if
+// needed. The data is written to a temporary file first and renamed over
+// the old file, so a failure doesn't leave half a file behind.
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}